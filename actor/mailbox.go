@@ -0,0 +1,96 @@
+package actor
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+// ErrClosed is returned when sending a message to a closed Mailbox.
+var ErrClosed = errors.New("actor: mailbox is closed")
+
+// Mailbox is the address of an actor; copies of a Mailbox always
+// refer to the same actor;
+// Messages sent to a Mailbox are received one at a time, in the order
+// they were sent, by a goroutine which has exclusive access to the
+// actor's state; The state is encapsulated, it can only be read or
+// modified while receiving a message.
+type Mailbox[M any] struct {
+	messages chan M
+	lock     *sync.RWMutex
+	closed   *bool
+	done     chan struct{}
+}
+
+// New() creates a new actor, and returns its Mailbox;
+// Each received message is handed to the receive function along with
+// a Portal to the actor's state, used exactly like the one provided by
+// sharef.Sharef.Do(); Writing nil kills the state, after which
+// received messages are discarded;
+// The Mailbox buffers up to capacity messages before Send() blocks;
+// New *panics* if:
+// 1: a pointer is provided as the state.
+func New[S any, M any](state S, capacity int, receive func(portal sharef.Portal[S], message M)) Mailbox[M] {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	shared := sharef.New(state)
+	closed := false
+	this := Mailbox[M]{
+		messages: make(chan M, capacity),
+		lock:     &sync.RWMutex{},
+		closed:   &closed,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		defer close(this.done)
+
+		for message := range this.messages {
+			shared.Do(func(portal sharef.Portal[S]) {
+				receive(portal, message)
+			})
+		}
+	}()
+
+	return this
+}
+
+// Send delivers a message to the actor, blocking while its Mailbox is
+// full;
+// Send returns ErrClosed if:
+// 1: the Mailbox was closed.
+// *Note*: sending a message to a full Mailbox from within its own
+// receive function deadlocks.
+func (this Mailbox[M]) Send(message M) error {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	if *this.closed {
+		return ErrClosed
+	}
+
+	this.messages <- message
+	return nil
+}
+
+// Close stops the Mailbox from accepting messages; The messages
+// already sent are still received, after which the actor stops;
+// Calling it more than once has no effect.
+func (this Mailbox[M]) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if !*this.closed {
+		*this.closed = true
+		close(this.messages)
+	}
+}
+
+// Done returns a channel which is closed once the actor has stopped,
+// after its Mailbox was closed and every message was received.
+func (this Mailbox[M]) Done() <-chan struct{} {
+	return this.done
+}
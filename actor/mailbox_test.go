@@ -0,0 +1,101 @@
+package actor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+func AssertPanic(body func(), message string, t *testing.T) {
+	panicked := false
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+
+		body()
+	}()
+
+	if !panicked {
+		t.Fatal(message)
+	}
+}
+
+func Test_Mailbox_New_Pointer_Panics(t *testing.T) {
+	AssertPanic(func() {
+		number := 10
+		New(&number, 0, func(portal sharef.Portal[*int], message int) {})
+	}, "Pointer should have caused a panic.", t)
+}
+
+func Test_Mailbox_Receives_In_Order(t *testing.T) {
+	cycles := 1000
+	received := make(chan []int, 1)
+
+	mailbox := New([]int{}, 10, func(portal sharef.Portal[[]int], message int) {
+		pointer := <-portal.Reader
+		values := append(*pointer, message)
+		if len(values) == cycles {
+			received <- values
+		}
+		portal.Writer <- &values
+	})
+
+	for index := 0; index < cycles; index++ {
+		if err := mailbox.Send(index); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mailbox.Close()
+	<-mailbox.Done()
+
+	for index, value := range <-received {
+		if value != index {
+			t.Fatal("Messages were received out of order.")
+		}
+	}
+}
+
+func Test_Mailbox_Close(t *testing.T) {
+	count := 0
+	mailbox := New(0, 10, func(portal sharef.Portal[int], message string) {
+		count++
+		portal.Writer <- <-portal.Reader
+	})
+
+	mailbox.Send("a")
+	mailbox.Send("b")
+	mailbox.Close()
+	mailbox.Close()
+
+	if err := mailbox.Send("c"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, but got: '%v'.", err)
+	}
+
+	<-mailbox.Done()
+	if count != 2 {
+		t.Errorf("Expected 2 messages to be received, but got: '%d'.", count)
+	}
+}
+
+func Test_Mailbox_Dead_State_Discards_Messages(t *testing.T) {
+	count := 0
+	mailbox := New(0, 10, func(portal sharef.Portal[int], message int) {
+		count++
+		<-portal.Reader
+		portal.Writer <- nil
+	})
+
+	mailbox.Send(1)
+	mailbox.Send(2)
+	mailbox.Close()
+	<-mailbox.Done()
+
+	if count != 1 {
+		t.Errorf("Expected 1 message to be received, but got: '%d'.", count)
+	}
+}
@@ -0,0 +1,338 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+// Agent holds a value which is updated asynchronously; copies of an
+// Agent always refer to the same value;
+// Actions sent to an Agent are queued and applied one at a time, in
+// the order they were sent, by a background goroutine, which only
+// runs while there are actions to be applied; Regular actions, sent
+// through Send, are applied on a pool shared by all Agents and bounded
+// by the number of processors, while blocking actions, sent through
+// SendOff, are applied on a separate, unbounded pool.
+type Agent[T any] struct {
+	state   sharef.Sharef[T]
+	mailbox *mailbox[T]
+}
+
+// ErrorMode determines how an Agent reacts to a failing action.
+type ErrorMode int
+
+const (
+	// Fail suspends the Agent when an action fails; Actions sent
+	// afterwards are queued, but only applied once the Agent is
+	// restarted.
+	Fail ErrorMode = iota
+	// Continue discards the failing action, keeping the Agent's
+	// value, and goes on applying the queued actions.
+	Continue
+)
+
+// action is an action queued for an Agent, along with whether it may
+// block.
+type action[T any] struct {
+	apply    func(T) T
+	blocking bool
+}
+
+// pool returns the pool the action is meant to run on.
+func (this action[T]) pool() *pool {
+	if this.blocking {
+		return blocking
+	}
+
+	return compute
+}
+
+// mailbox holds the actions queued for an Agent, along with its error
+// handling configuration, which are shared by all of its copies.
+type mailbox[T any] struct {
+	mutex   sync.Mutex
+	actions []action[T]
+	running bool
+	mode    ErrorMode
+	handler func(err error, action func(T) T, value T)
+	failure error
+	failed  chan struct{}
+}
+
+// New() creates a new Agent;
+// New *panics* if:
+// 1: a pointer is provided as its value.
+func New[T any](value T) Agent[T] {
+	return Agent[T]{
+		state:   sharef.NewAtomic(value),
+		mailbox: &mailbox[T]{failed: make(chan struct{})},
+	}
+}
+
+// Send queues an action to be applied to the Agent's value; The
+// value returned by the action becomes the Agent's new value;
+// Send returns immediately, without waiting for the action to be
+// applied; Actions sent to a suspended Agent are only applied once it
+// is restarted;
+// *Note*: actions sent through Send share a pool bounded by the
+// number of processors, so they must not block, or they hold up other
+// Agents' actions; Use SendOff() for those instead.
+func (this Agent[T]) Send(action func(T) T) {
+	this.enqueue(action, false)
+}
+
+// SendOff queues an action which may block, such as one performing
+// I/O, to be applied to the Agent's value;
+// It behaves like Send(), except the action is applied on a separate
+// pool, which grows as needed, unless capped through SetPoolSize(), so
+// blocking actions don't hold up the bounded pool applying regular
+// ones.
+func (this Agent[T]) SendOff(action func(T) T) {
+	this.enqueue(action, true)
+}
+
+// enqueue queues an action, and starts processing the queued actions
+// if needed.
+func (this Agent[T]) enqueue(apply func(T) T, blocking bool) {
+	this.mailbox.mutex.Lock()
+	defer this.mailbox.mutex.Unlock()
+
+	this.mailbox.actions = append(this.mailbox.actions, action[T]{
+		apply:    apply,
+		blocking: blocking,
+	})
+	this.start()
+}
+
+// SetErrorMode sets how the Agent reacts to a failing action; Agents
+// use the Fail mode by default.
+func (this Agent[T]) SetErrorMode(mode ErrorMode) {
+	this.mailbox.mutex.Lock()
+	defer this.mailbox.mutex.Unlock()
+
+	this.mailbox.mode = mode
+}
+
+// SetErrorHandler sets a callback function to be invoked whenever an
+// action fails, regardless of the Agent's error mode;
+// It receives the error, the failing action, and the value the action
+// was applied to.
+func (this Agent[T]) SetErrorHandler(handler func(err error, action func(T) T, value T)) {
+	this.mailbox.mutex.Lock()
+	defer this.mailbox.mutex.Unlock()
+
+	this.mailbox.handler = handler
+}
+
+// Failed returns the error which suspended the Agent, or nil if the
+// Agent is not suspended.
+func (this Agent[T]) Failed() error {
+	this.mailbox.mutex.Lock()
+	defer this.mailbox.mutex.Unlock()
+
+	return this.mailbox.failure
+}
+
+// Restart resumes a suspended Agent, setting its value; The actions
+// queued while the Agent was suspended are then applied;
+// Restart returns ErrNotFailed if:
+// 1: the Agent is not suspended.
+func (this Agent[T]) Restart(value T) error {
+	this.mailbox.mutex.Lock()
+	defer this.mailbox.mutex.Unlock()
+
+	if this.mailbox.failure == nil {
+		return ErrNotFailed
+	}
+
+	this.state.Swap(func(*T) *T {
+		return &value
+	})
+
+	this.mailbox.failure = nil
+	this.mailbox.failed = make(chan struct{})
+	this.start()
+
+	return nil
+}
+
+// Deref returns a copy of the Agent's latest committed value;
+// It never waits for an action being applied, as actions run outside
+// of the Agent's lock.
+func (this Agent[T]) Deref() T {
+	var value T
+
+	this.state.Swap(func(pointer *T) *T {
+		value = *pointer
+		return pointer
+	})
+
+	return value
+}
+
+// Flush blocks until every action sent so far to the Agent has been
+// applied;
+// It returns the Agent's error if it is, or becomes, suspended before
+// that.
+func (this Agent[T]) Flush() error {
+	return Await(context.Background(), this)
+}
+
+// Awaitable is implemented by Agents of any type, so Agents holding
+// different types of values can be awaited together.
+type Awaitable interface {
+	mark() chan struct{}
+	await(ctx context.Context, marker chan struct{}) error
+}
+
+// Await blocks until every action sent so far to the given Agents has
+// been applied;
+// It returns the context's error if it is done first, or the error of
+// the first Agent found to be suspended before its actions could be
+// applied.
+func Await(ctx context.Context, agents ...Awaitable) error {
+	markers := make([]chan struct{}, len(agents))
+	for index, agent := range agents {
+		markers[index] = agent.mark()
+	}
+
+	for index, agent := range agents {
+		if err := agent.await(ctx, markers[index]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mark queues an action which leaves the Agent's value untouched, and
+// returns a channel which is closed once that action is applied.
+func (this Agent[T]) mark() chan struct{} {
+	marker := make(chan struct{})
+
+	this.Send(func(value T) T {
+		close(marker)
+		return value
+	})
+
+	return marker
+}
+
+// await blocks until the given marker is closed, the context is done,
+// or the Agent is suspended.
+func (this Agent[T]) await(ctx context.Context, marker chan struct{}) error {
+	for {
+		this.mailbox.mutex.Lock()
+		failure, failed := this.mailbox.failure, this.mailbox.failed
+		this.mailbox.mutex.Unlock()
+
+		if failure != nil {
+			return failure
+		}
+
+		select {
+		case <-marker:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-failed:
+		}
+	}
+}
+
+// start starts processing the queued actions, unless they're already
+// being processed or the Agent is suspended;
+// The mailbox's mutex must be held by the caller.
+func (this Agent[T]) start() {
+	if !this.mailbox.running && this.mailbox.failure == nil && len(this.mailbox.actions) > 0 {
+		this.mailbox.running = true
+		compute.submit(func() {
+			this.process(false)
+		})
+	}
+}
+
+// process applies the queued actions until there are none left, or
+// the Agent is suspended; It runs on the blocking pool if blocking is
+// set, and on the compute pool otherwise;
+// Whenever the next action is meant to run on the other pool,
+// processing is handed over to it, so actions are still applied one
+// at a time, in order.
+func (this Agent[T]) process(blocking bool) {
+	for {
+		this.mailbox.mutex.Lock()
+		if len(this.mailbox.actions) == 0 {
+			this.mailbox.running = false
+			this.mailbox.mutex.Unlock()
+			return
+		}
+		next := this.mailbox.actions[0]
+		if next.blocking != blocking {
+			this.mailbox.mutex.Unlock()
+			next.pool().submit(func() {
+				this.process(next.blocking)
+			})
+			return
+		}
+		this.mailbox.actions = this.mailbox.actions[1:]
+		this.mailbox.mutex.Unlock()
+
+		value, err := this.apply(next.apply)
+		if err == nil {
+			continue
+		}
+
+		this.mailbox.mutex.Lock()
+		handler, mode := this.mailbox.handler, this.mailbox.mode
+		this.mailbox.mutex.Unlock()
+
+		if handler != nil {
+			handler(err, next.apply, value)
+		}
+
+		if mode == Fail {
+			this.mailbox.mutex.Lock()
+			this.mailbox.failure = err
+			this.mailbox.running = false
+			close(this.mailbox.failed)
+			this.mailbox.mutex.Unlock()
+			return
+		}
+	}
+}
+
+// apply applies a single action to the Agent's value;
+// The value is read, and the action's result committed, under the
+// Agent's lock, but the action itself runs outside of it, so Deref()
+// isn't held up by it; This is safe because only the goroutine
+// processing the Agent's actions ever writes to its value;
+// It returns the value the action was applied to, and an error
+// wrapping ErrActionPanicked if the action panicked, in which case
+// the Agent keeps its value.
+func (this Agent[T]) apply(action func(T) T) (T, error) {
+	value := this.Deref()
+
+	next, err := run(action, value)
+	if err == nil {
+		this.state.Swap(func(*T) *T {
+			return &next
+		})
+	}
+
+	return value, err
+}
+
+// run runs an action, turning a panic into an error wrapping
+// ErrActionPanicked.
+func run[T any](action func(T) T, value T) (next T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrActionPanicked, r)
+		}
+	}()
+
+	return action(value), nil
+}
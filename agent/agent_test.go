@@ -0,0 +1,378 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func AssertPanic(body func(), message string, t *testing.T) {
+	panicked := false
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+
+		body()
+	}()
+
+	if !panicked {
+		t.Fatal(message)
+	}
+}
+
+// eventually polls a condition until it holds, or fails the test.
+func eventually(condition func() bool, message string, t *testing.T) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(message)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func Test_Agent_New_Pointer_Panics(t *testing.T) {
+	AssertPanic(func() {
+		number := 10
+		New(&number)
+	}, "Pointer should have caused a panic.", t)
+}
+
+func Test_Agent_Deref(t *testing.T) {
+	agent := New(10)
+
+	if agent.Deref() != 10 {
+		t.Errorf("Value should be 10, but instead it was: '%d'.", agent.Deref())
+	}
+}
+
+func Test_Agent_Deref_During_Blocking_Action(t *testing.T) {
+	agent := New(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	agent.Send(func(value int) int {
+		close(started)
+		<-release
+		return value + 1
+	})
+	<-started
+
+	dereferenced := make(chan int)
+	go func() {
+		dereferenced <- agent.Deref()
+	}()
+
+	select {
+	case value := <-dereferenced:
+		if value != 1 {
+			t.Errorf("Value should be 1, but instead it was: '%d'.", value)
+		}
+	case <-time.After(time.Second):
+		t.Error("Deref() should not wait for the action in flight.")
+	}
+
+	close(release)
+	if err := agent.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if agent.Deref() != 2 {
+		t.Errorf("Value should be 2, but instead it was: '%d'.", agent.Deref())
+	}
+}
+
+func Test_Agent_Send_Applies_Actions_In_Order(t *testing.T) {
+	cycles := 1000
+
+	agent := New([]int{})
+	for index := 0; index < cycles; index++ {
+		value := index
+		agent.Send(func(values []int) []int {
+			return append(values, value)
+		})
+	}
+
+	eventually(func() bool {
+		return len(agent.Deref()) == cycles
+	}, "Not every action was applied.", t)
+
+	for index, value := range agent.Deref() {
+		if value != index {
+			t.Fatalf("Actions were applied out of order: '%v'.", agent.Deref())
+		}
+	}
+}
+
+func Test_Agent_Copies_Share_State(t *testing.T) {
+	agent := New(0)
+	copy := agent
+
+	copy.Send(func(value int) int {
+		return value + 1
+	})
+
+	eventually(func() bool {
+		return agent.Deref() == 1
+	}, "Copy should have updated the original Agent.", t)
+}
+
+func Test_Agent_Fail_Mode(t *testing.T) {
+	agent := New(1)
+
+	agent.Send(func(value int) int {
+		panic("boom")
+	})
+	agent.Send(func(value int) int {
+		return value + 1
+	})
+
+	eventually(func() bool {
+		return agent.Failed() != nil
+	}, "Agent should have failed.", t)
+
+	if !errors.Is(agent.Failed(), ErrActionPanicked) {
+		t.Errorf("Expected ErrActionPanicked, but got: '%v'.", agent.Failed())
+	}
+
+	if agent.Deref() != 1 {
+		t.Errorf("Value should be 1, but instead it was: '%d'.", agent.Deref())
+	}
+
+	if err := agent.Restart(10); err != nil {
+		t.Fatal(err)
+	}
+
+	// The action queued while the Agent was suspended is applied after
+	// restarting.
+	eventually(func() bool {
+		return agent.Deref() == 11
+	}, "Queued action should have been applied after restarting.", t)
+
+	if agent.Failed() != nil {
+		t.Errorf("Agent should not be failed, but got: '%v'.", agent.Failed())
+	}
+}
+
+func Test_Agent_Continue_Mode(t *testing.T) {
+	agent := New(1)
+	agent.SetErrorMode(Continue)
+
+	failures := make(chan int, 1)
+	agent.SetErrorHandler(func(err error, action func(int) int, value int) {
+		failures <- value
+	})
+
+	agent.Send(func(value int) int {
+		panic("boom")
+	})
+	agent.Send(func(value int) int {
+		return value + 1
+	})
+
+	if value := <-failures; value != 1 {
+		t.Errorf("Handler should have received 1, but instead it got: '%d'.", value)
+	}
+
+	eventually(func() bool {
+		return agent.Deref() == 2
+	}, "Action after the failing one should have been applied.", t)
+
+	if agent.Failed() != nil {
+		t.Errorf("Agent should not be failed, but got: '%v'.", agent.Failed())
+	}
+}
+
+func Test_Agent_Restart_Not_Failed(t *testing.T) {
+	agent := New(1)
+
+	if err := agent.Restart(2); !errors.Is(err, ErrNotFailed) {
+		t.Errorf("Expected ErrNotFailed, but got: '%v'.", err)
+	}
+}
+
+func Test_Agent_Flush(t *testing.T) {
+	agent := New(0)
+
+	for index := 0; index < 100; index++ {
+		agent.Send(func(value int) int {
+			time.Sleep(time.Microsecond)
+			return value + 1
+		})
+	}
+
+	if err := agent.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if agent.Deref() != 100 {
+		t.Errorf("Value should be 100, but instead it was: '%d'.", agent.Deref())
+	}
+}
+
+func Test_Agent_Await(t *testing.T) {
+	agents := []Agent[int]{New(0), New(0), New(0)}
+
+	for _, agent := range agents {
+		for index := 0; index < 10; index++ {
+			agent.Send(func(value int) int {
+				return value + 1
+			})
+		}
+	}
+
+	if err := Await(context.Background(), agents[0], agents[1], agents[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, agent := range agents {
+		if agent.Deref() != 10 {
+			t.Errorf("Value should be 10, but instead it was: '%d'.", agent.Deref())
+		}
+	}
+}
+
+func Test_Agent_Await_Different_Types(t *testing.T) {
+	counter, name := New(0), New("")
+
+	counter.Send(func(value int) int {
+		return value + 1
+	})
+	name.Send(func(value string) string {
+		return value + "agent"
+	})
+
+	if err := Await(context.Background(), counter, name); err != nil {
+		t.Fatal(err)
+	}
+
+	if counter.Deref() != 1 || name.Deref() != "agent" {
+		t.Errorf("Unexpected values: '%d', '%s'.", counter.Deref(), name.Deref())
+	}
+}
+
+func Test_Agent_Await_Failed(t *testing.T) {
+	agent := New(0)
+	agent.Send(func(value int) int {
+		panic("boom")
+	})
+
+	if err := agent.Flush(); !errors.Is(err, ErrActionPanicked) {
+		t.Errorf("Expected ErrActionPanicked, but got: '%v'.", err)
+	}
+}
+
+func Test_Agent_Await_Context(t *testing.T) {
+	agent := New(0)
+	release := make(chan struct{})
+	defer close(release)
+
+	agent.Send(func(value int) int {
+		<-release
+		return value
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := Await(ctx, agent); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+}
+
+func Test_Agent_SendOff_Keeps_Order(t *testing.T) {
+	agent := New([]int{})
+
+	for index := 0; index < 100; index++ {
+		value := index
+		action := func(values []int) []int {
+			return append(values, value)
+		}
+
+		if index%3 == 0 {
+			agent.SendOff(action)
+		} else {
+			agent.Send(action)
+		}
+	}
+
+	if err := agent.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	for index, value := range agent.Deref() {
+		if value != index {
+			t.Fatalf("Actions were applied out of order: '%v'.", agent.Deref())
+		}
+	}
+}
+
+func Test_Agent_SendOff_Does_Not_Hold_Up_Send(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	// Enough blocking actions to fill every slot of the pool applying
+	// regular actions, were they sent through Send.
+	var started sync.WaitGroup
+	for index := 0; index < runtime.GOMAXPROCS(0); index++ {
+		started.Add(1)
+		New(0).SendOff(func(value int) int {
+			started.Done()
+			<-release
+			return value + 1
+		})
+	}
+	started.Wait()
+
+	responsive := New(0)
+	responsive.Send(func(value int) int {
+		return value + 1
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := Await(ctx, responsive); err != nil {
+		t.Fatalf("Regular action should not wait for blocking ones, but got: '%v'.", err)
+	}
+}
+
+func Test_Agent_SetPoolSize(t *testing.T) {
+	SetPoolSize(1)
+	defer SetPoolSize(0)
+
+	first, second := New(0), New(0)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first.SendOff(func(value int) int {
+		close(started)
+		<-release
+		return value + 1
+	})
+	<-started
+
+	second.SendOff(func(value int) int {
+		return value + 1
+	})
+
+	time.Sleep(10 * time.Millisecond)
+	if second.Deref() != 0 {
+		t.Error("Second blocking action should wait for a pool slot.")
+	}
+
+	close(release)
+	if err := Await(context.Background(), first, second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Deref() != 1 || second.Deref() != 1 {
+		t.Error("Both actions should have been applied.")
+	}
+}
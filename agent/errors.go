@@ -0,0 +1,13 @@
+package agent
+
+import "errors"
+
+var (
+	// ErrActionPanicked is reported when an action sent to an Agent
+	// panics.
+	ErrActionPanicked = errors.New("agent: action panicked")
+
+	// ErrNotFailed is returned when restarting an Agent which has not
+	// failed.
+	ErrNotFailed = errors.New("agent: agent has not failed")
+)
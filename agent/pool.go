@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"runtime"
+	"sync"
+)
+
+// pool runs the tasks processing Agents' actions; Its goroutines are
+// started on demand, and the number of them running at once is
+// bounded by the pool's slots, unless it has none, in which case it
+// is unbounded.
+type pool struct {
+	mutex sync.Mutex
+	slots chan struct{}
+}
+
+// compute runs the actions sent through Send; It is bounded by the
+// number of processors, so CPU-bound actions don't oversubscribe
+// them.
+var compute = &pool{
+	slots: make(chan struct{}, runtime.GOMAXPROCS(0)),
+}
+
+// blocking runs the actions sent through SendOff; It is unbounded,
+// unless capped through SetPoolSize(), so actions blocked on I/O
+// never hold up other Agents.
+var blocking = &pool{}
+
+// SetPoolSize caps the number of blocking actions, sent through
+// SendOff, which may run at once across all Agents; Sizes lower than
+// 1 remove the cap, which is the default;
+// Actions which are already running are not affected.
+func SetPoolSize(size int) {
+	blocking.mutex.Lock()
+	defer blocking.mutex.Unlock()
+
+	if size < 1 {
+		blocking.slots = nil
+	} else {
+		blocking.slots = make(chan struct{}, size)
+	}
+}
+
+// submit runs a task on the pool, as soon as one of its slots is
+// available.
+func (this *pool) submit(task func()) {
+	this.mutex.Lock()
+	slots := this.slots
+	this.mutex.Unlock()
+
+	go func() {
+		if slots != nil {
+			slots <- struct{}{}
+			defer func() {
+				<-slots
+			}()
+		}
+
+		task()
+	}()
+}
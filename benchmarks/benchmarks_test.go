@@ -0,0 +1,153 @@
+package benchmarks
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/martinjungblut/gobox/fastatom"
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+// workload describes how often an operation is a write: one in every
+// `every` operations writes, the others read.
+type workload struct {
+	name  string
+	every int
+}
+
+var workloads = []workload{
+	{name: "ReadHeavy", every: 10},
+	{name: "Mixed", every: 2},
+	{name: "WriteHeavy", every: 1},
+}
+
+var goroutineCounts = []int{1, 8, 64}
+
+// primitive adapts a shared counter to the benchmarks; read and write
+// are called concurrently.
+type primitive struct {
+	name  string
+	setup func() (read func() int, write func())
+}
+
+var primitives = []primitive{
+	{
+		name: "Mutex",
+		setup: func() (func() int, func()) {
+			var mutex sync.RWMutex
+			value := 0
+			read := func() int {
+				mutex.RLock()
+				defer mutex.RUnlock()
+				return value
+			}
+			write := func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				value++
+			}
+			return read, write
+		},
+	},
+	{
+		name: "SharefDo",
+		setup: func() (func() int, func()) {
+			counter := sharef.NewAtomic(0)
+			read := func() (value int) {
+				counter.Do(func(portal sharef.Portal[int]) {
+					pointer := <-portal.Reader
+					value = *pointer
+					portal.Writer <- pointer
+				})
+				return value
+			}
+			write := func() {
+				counter.Do(func(portal sharef.Portal[int]) {
+					value := *<-portal.Reader + 1
+					portal.Writer <- &value
+				})
+			}
+			return read, write
+		},
+	},
+	{
+		name: "SharefSwap",
+		setup: func() (func() int, func()) {
+			counter := sharef.NewAtomic(0)
+			read := func() (value int) {
+				counter.Swap(func(pointer *int) *int {
+					value = *pointer
+					return pointer
+				})
+				return value
+			}
+			write := func() {
+				counter.Swap(func(pointer *int) *int {
+					value := *pointer + 1
+					return &value
+				})
+			}
+			return read, write
+		},
+	},
+	{
+		name: "FastatomValue",
+		setup: func() (func() int, func()) {
+			counter := fastatom.New(0)
+			read := func() int {
+				value, _ := counter.Load()
+				return value
+			}
+			write := func() {
+				counter.Update(func(value int) int { return value + 1 })
+			}
+			return read, write
+		},
+	},
+}
+
+func Benchmark_Primitives(b *testing.B) {
+	for _, primitive := range primitives {
+		for _, workload := range workloads {
+			for _, goroutines := range goroutineCounts {
+				primitive, workload, goroutines := primitive, workload, goroutines
+				name := primitive.name + "/" + workload.name + "/" + strconv.Itoa(goroutines)
+
+				b.Run(name, func(b *testing.B) {
+					read, write := primitive.setup()
+					run(b, goroutines, workload.every, read, write)
+				})
+			}
+		}
+	}
+}
+
+// run spreads b.N operations across the given number of goroutines,
+// one in every `every` operations being a write.
+func run(b *testing.B, goroutines int, every int, read func() int, write func()) {
+	var wg sync.WaitGroup
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for goroutine := 0; goroutine < goroutines; goroutine++ {
+		operations := b.N / goroutines
+		if goroutine < b.N%goroutines {
+			operations++
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for operation := 0; operation < operations; operation++ {
+				if operation%every == 0 {
+					write()
+				} else {
+					read()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
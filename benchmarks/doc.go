@@ -0,0 +1,6 @@
+// Package benchmarks compares the cost of gobox's shared state
+// primitives under read-heavy, write-heavy and mixed workloads, at
+// varying goroutine counts;
+// It holds no code of its own, run it with:
+// go test -run NONE -bench . -benchmem ./benchmarks
+package benchmarks
@@ -0,0 +1,90 @@
+package gobox
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanicked is reported when the function computing a Delayed's
+// value panics.
+var ErrPanicked = errors.New("gobox: function panicked")
+
+// Delayed is a value computed on first access, and cached afterwards;
+// copies of a Delayed always refer to the same value;
+// Once computed, the value is read-only: every access returns a copy
+// of it.
+type Delayed[T any] struct {
+	state *delayed[T]
+}
+
+// delayed is the state of a Delayed, shared by all of its copies.
+type delayed[T any] struct {
+	once     sync.Once
+	init     func() (T, error)
+	value    T
+	err      error
+	realized bool
+	mutex    sync.RWMutex
+}
+
+// Delay() creates a new Delayed, whose value is computed by the given
+// function on first access;
+// Concurrent first accesses collapse into a single invocation of the
+// function, whose result, value or error, is cached;
+// Delay *panics* if:
+// 1: the function is nil.
+func Delay[T any](init func() (T, error)) Delayed[T] {
+	if init == nil {
+		panic("Invalid state: function is nil.")
+	}
+
+	return Delayed[T]{
+		state: &delayed[T]{init: init},
+	}
+}
+
+// Force returns the Delayed's value, computing it if needed;
+// It blocks while the value is being computed by another goroutine;
+// If the computation failed, its error is returned instead, on this
+// and every later access; A panicking computation fails with an error
+// wrapping ErrPanicked.
+func (this Delayed[T]) Force() (T, error) {
+	this.state.once.Do(func() {
+		value, err := compute(this.state.init)
+
+		this.state.mutex.Lock()
+		defer this.state.mutex.Unlock()
+
+		this.state.value, this.state.err = value, err
+		this.state.realized = true
+		this.state.init = nil
+	})
+
+	this.state.mutex.RLock()
+	defer this.state.mutex.RUnlock()
+
+	return this.state.value, this.state.err
+}
+
+// IsRealized returns whether the Delayed's value was already
+// computed, without computing it.
+func (this Delayed[T]) IsRealized() bool {
+	this.state.mutex.RLock()
+	defer this.state.mutex.RUnlock()
+
+	return this.state.realized
+}
+
+// compute runs a Delayed's function, turning a panic into an error
+// wrapping ErrPanicked.
+func compute[T any](init func() (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			value, err = zero, fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+
+	return init()
+}
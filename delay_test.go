@@ -0,0 +1,116 @@
+package gobox
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func AssertPanic(body func(), message string, t *testing.T) {
+	panicked := false
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+
+		body()
+	}()
+
+	if !panicked {
+		t.Fatal(message)
+	}
+}
+
+func Test_Delay_Nil_Panics(t *testing.T) {
+	AssertPanic(func() {
+		Delay[int](nil)
+	}, "Nil function should have caused a panic.", t)
+}
+
+func Test_Delay_Force(t *testing.T) {
+	delayed := Delay(func() (int, error) {
+		return 10, nil
+	})
+
+	if delayed.IsRealized() {
+		t.Error("Value should not have been computed yet.")
+	}
+
+	value, err := delayed.Force()
+	if err != nil || value != 10 {
+		t.Errorf("Expected '10', but got: '%d', '%v'.", value, err)
+	}
+
+	if !delayed.IsRealized() {
+		t.Error("Value should have been computed.")
+	}
+}
+
+func Test_Delay_Force_Concurrently_Computes_Once(t *testing.T) {
+	var calls atomic.Int32
+
+	delayed := Delay(func() (int, error) {
+		calls.Add(1)
+		return 10, nil
+	})
+
+	wg := sync.WaitGroup{}
+	wg.Add(100)
+	for index := 0; index < 100; index++ {
+		go func() {
+			defer wg.Done()
+
+			if value, _ := delayed.Force(); value != 10 {
+				t.Errorf("Expected '10', but got: '%d'.", value)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Function should have been called once, but was called '%d' times.", calls.Load())
+	}
+}
+
+func Test_Delay_Force_Caches_Error(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+
+	delayed := Delay(func() (int, error) {
+		calls++
+		return 0, errBoom
+	})
+
+	for index := 0; index < 2; index++ {
+		if _, err := delayed.Force(); !errors.Is(err, errBoom) {
+			t.Errorf("Expected the function's error, but got: '%v'.", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Function should have been called once, but was called '%d' times.", calls)
+	}
+}
+
+func Test_Delay_Force_Caches_Panic(t *testing.T) {
+	calls := 0
+
+	delayed := Delay(func() (int, error) {
+		calls++
+		panic("boom")
+	})
+
+	for index := 0; index < 2; index++ {
+		if _, err := delayed.Force(); !errors.Is(err, ErrPanicked) {
+			t.Errorf("Expected ErrPanicked, but got: '%v'.", err)
+		}
+	}
+
+	if calls != 1 || !delayed.IsRealized() {
+		t.Errorf("Function should have been called once, but was called '%d' times.", calls)
+	}
+}
@@ -0,0 +1,145 @@
+package fastatom
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNilState is reported when operating on a Value whose state was
+// never originally provided (zero value).
+var ErrNilState = errors.New("fastatom: value is nil")
+
+// Value is a lock-free shared reference, built directly on sync/atomic;
+// copies of a Value always refer to the same value, so a modification
+// to any copy implies a state mutation across all copies;
+// Values are replaced as a whole, never modified in place, so Value is
+// best suited for pointer-sized or immutable types, where a mutex
+// based Sharef shows up in profiles;
+// Storing nil kills the Value, like writing nil kills a Sharef.
+type Value[T any] struct {
+	state *atomic.Pointer[T]
+}
+
+// New() creates a new Value.
+func New[T any](value T) Value[T] {
+	instance := Dead[T]()
+	instance.state.Store(&value)
+	return instance
+}
+
+// Dead() creates a new dead Value, which holds no value.
+func Dead[T any]() Value[T] {
+	return Value[T]{
+		state: &atomic.Pointer[T]{},
+	}
+}
+
+// Load returns the Value's current value;
+// It returns false, along with T's zero value, if the Value is dead;
+// Load *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) Load() (T, bool) {
+	if current := this.pointer().Load(); current != nil {
+		return *current, true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Store replaces the Value's current value, reviving it if it was
+// dead;
+// Store *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) Store(value T) {
+	this.pointer().Store(&value)
+}
+
+// Swap replaces the Value's current value, and returns the previous
+// one;
+// It returns false, along with T's zero value, if the Value was dead;
+// Swap *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) Swap(value T) (T, bool) {
+	if previous := this.pointer().Swap(&value); previous != nil {
+		return *previous, true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// Update atomically applies a given function to the Value's current
+// value, retrying whenever a concurrent write wins the race, so the
+// function must be free of side effects;
+// It returns false, without executing the function, if the Value is
+// dead;
+// Update *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) Update(body func(T) T) bool {
+	state := this.pointer()
+
+	for {
+		current := state.Load()
+		if current == nil {
+			return false
+		}
+
+		updated := body(*current)
+		if state.CompareAndSwap(current, &updated) {
+			return true
+		}
+	}
+}
+
+// Kill atomically kills the Value, so it no longer holds a value;
+// Kill *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) Kill() {
+	this.pointer().Store(nil)
+}
+
+// IsAlive returns whether the Value holds a value;
+// IsAlive *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) IsAlive() bool {
+	return this.pointer().Load() != nil
+}
+
+// IsDead returns whether the Value holds no value;
+// IsDead *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func (this Value[T]) IsDead() bool {
+	return !this.IsAlive()
+}
+
+// CompareAndSwap replaces the Value's current value with a new one,
+// only if the current value equals the old one;
+// It returns whether the value was replaced, and always returns false
+// if the Value is dead;
+// CompareAndSwap *panics* if:
+// 1: the Value's state was never originally provided (zero value).
+func CompareAndSwap[T comparable](value Value[T], old T, new T) bool {
+	state := value.pointer()
+
+	for {
+		current := state.Load()
+		if current == nil || *current != old {
+			return false
+		}
+
+		if state.CompareAndSwap(current, &new) {
+			return true
+		}
+	}
+}
+
+// pointer returns the Value's atomic pointer, *panicking* if the
+// Value's state was never originally provided (zero value).
+func (this Value[T]) pointer() *atomic.Pointer[T] {
+	if this.state == nil {
+		panic(ErrNilState)
+	}
+
+	return this.state
+}
@@ -0,0 +1,133 @@
+package fastatom
+
+import (
+	"sync"
+	"testing"
+)
+
+func AssertPanic(body func(), message string, t *testing.T) {
+	panicked := false
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+
+		body()
+	}()
+
+	if !panicked {
+		t.Fatal(message)
+	}
+}
+
+func Test_Value_Load_Store(t *testing.T) {
+	value := New(10)
+	copied := value
+
+	copied.Store(20)
+
+	if current, alive := value.Load(); !alive || current != 20 {
+		t.Errorf("Expected '20', but got: '%d', '%t'.", current, alive)
+	}
+}
+
+func Test_Value_Swap(t *testing.T) {
+	value := New(10)
+
+	if previous, alive := value.Swap(20); !alive || previous != 10 {
+		t.Errorf("Expected '10', but got: '%d', '%t'.", previous, alive)
+	}
+	if current, _ := value.Load(); current != 20 {
+		t.Errorf("Expected '20', but got: '%d'.", current)
+	}
+}
+
+func Test_Value_Kill_And_Revive(t *testing.T) {
+	value := New(10)
+	value.Kill()
+
+	if value.IsAlive() || !value.IsDead() {
+		t.Fatal("Killed Value should have been dead.")
+	}
+	if current, alive := value.Load(); alive || current != 0 {
+		t.Errorf("Expected a dead zero value, but got: '%d', '%t'.", current, alive)
+	}
+	if value.Update(func(current int) int { return current + 1 }) {
+		t.Error("Update on a dead Value should have had no effect.")
+	}
+	if CompareAndSwap(value, 0, 1) {
+		t.Error("CompareAndSwap on a dead Value should have had no effect.")
+	}
+
+	value.Store(30)
+	if current, alive := value.Load(); !alive || current != 30 {
+		t.Errorf("Expected '30', but got: '%d', '%t'.", current, alive)
+	}
+}
+
+func Test_Value_Dead(t *testing.T) {
+	value := Dead[string]()
+
+	if !value.IsDead() {
+		t.Error("Dead Value should have been dead.")
+	}
+	if _, alive := value.Swap("revived"); alive {
+		t.Error("Swap on a dead Value should have reported it as dead.")
+	}
+	if current, alive := value.Load(); !alive || current != "revived" {
+		t.Errorf("Expected 'revived', but got: '%s', '%t'.", current, alive)
+	}
+}
+
+func Test_Value_Update_Concurrent(t *testing.T) {
+	value := New(0)
+	var wg sync.WaitGroup
+
+	for index := 0; index < 64; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for count := 0; count < 100; count++ {
+				value.Update(func(current int) int { return current + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if current, _ := value.Load(); current != 6400 {
+		t.Errorf("Expected '6400', but got: '%d'.", current)
+	}
+}
+
+func Test_Value_CompareAndSwap(t *testing.T) {
+	value := New("a")
+
+	if CompareAndSwap(value, "b", "c") {
+		t.Error("CompareAndSwap should have failed on a different old value.")
+	}
+	if !CompareAndSwap(value, "a", "c") {
+		t.Error("CompareAndSwap should have succeeded on an equal old value.")
+	}
+	if current, _ := value.Load(); current != "c" {
+		t.Errorf("Expected 'c', but got: '%s'.", current)
+	}
+}
+
+func Test_Value_Zero_Value_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var value Value[int]
+		value.Load()
+	}, "Zero value should have caused a panic.", t)
+}
+
+func Benchmark_Value_Update(b *testing.B) {
+	value := New(0)
+	b.ReportAllocs()
+
+	for index := 0; index < b.N; index++ {
+		value.Update(func(current int) int { return current + 1 })
+	}
+}
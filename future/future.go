@@ -0,0 +1,129 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanicked is reported when the function chained through Then()
+// panics.
+var ErrPanicked = errors.New("future: function panicked")
+
+// Future holds the result of an asynchronous computation, which
+// becomes available once it is either completed with a value or
+// failed with an error; copies of a Future always refer to the same
+// result;
+// A Future is resolved at most once, later attempts have no effect.
+type Future[T any] struct {
+	result *result[T]
+}
+
+// result is the outcome of a Future, shared by all of its copies.
+type result[T any] struct {
+	once  sync.Once
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// New() creates a new, unresolved Future.
+func New[T any]() Future[T] {
+	return Future[T]{
+		result: &result[T]{
+			done: make(chan struct{}),
+		},
+	}
+}
+
+// Complete resolves the Future with a value;
+// It returns whether the Future was resolved by this call.
+func (this Future[T]) Complete(value T) bool {
+	return this.resolve(value, nil)
+}
+
+// Fail resolves the Future with an error;
+// It returns whether the Future was resolved by this call;
+// Fail *panics* if:
+// 1: the error is nil.
+func (this Future[T]) Fail(err error) bool {
+	if err == nil {
+		panic("Invalid state: error is nil.")
+	}
+
+	var zero T
+	return this.resolve(zero, err)
+}
+
+// Get blocks until the Future is resolved, and returns its value or
+// error;
+// It returns the context's error if it is done first.
+func (this Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-this.result.done:
+		return this.result.value, this.result.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel which is closed once the Future is resolved.
+func (this Future[T]) Done() <-chan struct{} {
+	return this.result.done
+}
+
+// Then returns a new Future, resolved by applying a function to the
+// value of the given Future once it is completed;
+// If the given Future fails, or the function returns an error, the
+// new Future fails with that error, and the function is not applied
+// in the former case; If the function panics, the new Future fails
+// with an error wrapping ErrPanicked.
+func Then[A any, B any](future Future[A], body func(A) (B, error)) Future[B] {
+	next := New[B]()
+
+	go func() {
+		<-future.result.done
+		if future.result.err != nil {
+			next.Fail(future.result.err)
+			return
+		}
+
+		value, err := apply(body, future.result.value)
+		if err != nil {
+			next.Fail(err)
+			return
+		}
+		next.Complete(value)
+	}()
+
+	return next
+}
+
+// apply applies the function chained through Then(), turning a panic
+// into an error wrapping ErrPanicked.
+func apply[A any, B any](body func(A) (B, error), value A) (result B, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero B
+			result, err = zero, fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+
+	return body(value)
+}
+
+// resolve sets the Future's outcome, unless it was already resolved.
+func (this Future[T]) resolve(value T, err error) bool {
+	resolved := false
+
+	this.result.once.Do(func() {
+		this.result.value = value
+		this.result.err = err
+		close(this.result.done)
+		resolved = true
+	})
+
+	return resolved
+}
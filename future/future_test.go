@@ -0,0 +1,130 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AssertPanic(body func(), message string, t *testing.T) {
+	panicked := false
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				panicked = true
+			}
+		}()
+
+		body()
+	}()
+
+	if !panicked {
+		t.Fatal(message)
+	}
+}
+
+func Test_Future_Complete(t *testing.T) {
+	future := New[int]()
+
+	go func() {
+		future.Complete(10)
+	}()
+
+	value, err := future.Get(context.Background())
+	if err != nil || value != 10 {
+		t.Errorf("Expected '10', but got: '%d', '%v'.", value, err)
+	}
+}
+
+func Test_Future_Fail(t *testing.T) {
+	errBoom := errors.New("boom")
+	future := New[int]()
+	future.Fail(errBoom)
+
+	if _, err := future.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("Expected the failure's error, but got: '%v'.", err)
+	}
+}
+
+func Test_Future_Fail_Nil_Panics(t *testing.T) {
+	AssertPanic(func() {
+		New[int]().Fail(nil)
+	}, "Nil error should have caused a panic.", t)
+}
+
+func Test_Future_Resolves_Once(t *testing.T) {
+	future := New[int]()
+
+	if !future.Complete(1) {
+		t.Error("First resolution should have succeeded.")
+	}
+	if future.Complete(2) || future.Fail(errors.New("boom")) {
+		t.Error("Later resolutions should have had no effect.")
+	}
+
+	value, err := future.Get(context.Background())
+	if err != nil || value != 1 {
+		t.Errorf("Expected '1', but got: '%d', '%v'.", value, err)
+	}
+}
+
+func Test_Future_Get_Context(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := New[int]().Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+}
+
+func Test_Future_Then(t *testing.T) {
+	future := New[int]()
+	chained := Then(future, func(value int) (string, error) {
+		return strconv.Itoa(value * 2), nil
+	})
+
+	future.Complete(21)
+
+	value, err := chained.Get(context.Background())
+	if err != nil || value != "42" {
+		t.Errorf("Expected '42', but got: '%s', '%v'.", value, err)
+	}
+}
+
+func Test_Future_Then_Propagates_Failure(t *testing.T) {
+	errBoom := errors.New("boom")
+	applied := false
+
+	future := New[int]()
+	chained := Then(future, func(value int) (int, error) {
+		applied = true
+		return value, nil
+	})
+
+	future.Fail(errBoom)
+
+	if _, err := chained.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("Expected the failure's error, but got: '%v'.", err)
+	}
+	if applied {
+		t.Error("Function should not have been applied to a failed Future.")
+	}
+}
+
+func Test_Future_Then_Recovers_Panic(t *testing.T) {
+	future := New[int]()
+	chained := Then(future, func(value int) (int, error) {
+		panic("boom")
+	})
+
+	future.Complete(21)
+
+	_, err := chained.Get(context.Background())
+	if !errors.Is(err, ErrPanicked) || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected ErrPanicked, but got: '%v'.", err)
+	}
+}
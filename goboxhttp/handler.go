@@ -0,0 +1,109 @@
+package goboxhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+// Inspector is a group of named values which can be inspected over
+// HTTP; *sharef.Group implements it.
+type Inspector interface {
+	// Name returns the group's name.
+	Name() string
+	// Names returns the names of the group's members.
+	Names() []string
+	// MarshalJSON encodes the group as an object mapping the name of
+	// each member to its current value.
+	MarshalJSON() ([]byte, error)
+	// Stats returns the stats of each member, indexed by name.
+	Stats() map[string]sharef.Stats
+	// MarshalEventsJSON encodes the group's recent events as an array,
+	// oldest first.
+	MarshalEventsJSON() ([]byte, error)
+}
+
+// summary describes a group in the listing served by Handler.
+type summary struct {
+	Name    string   `json:"name"`
+	Len     int      `json:"len"`
+	Members []string `json:"members"`
+}
+
+// Handler returns an http.Handler exposing the given groups as JSON:
+// its root lists every group, along with the number and names of its
+// members, "/<group name>" serves the current value of each member of
+// that group, "/<group name>/stats" serves their stats, and
+// "/<group name>/events" serves the group's recent events, which are
+// only available if the group retains them, e.g. through
+// sharef.Group's RetainEvents();
+// It is meant to be mounted with its prefix stripped, e.g.:
+// mux.Handle("/debug/gobox/", http.StripPrefix("/debug/gobox", Handler(&group))).
+func Handler(groups ...Inspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		name, view, _ := strings.Cut(strings.Trim(r.URL.Path, "/"), "/")
+		if name == "" {
+			summaries := make([]summary, 0, len(groups))
+			for _, group := range groups {
+				names := group.Names()
+				summaries = append(summaries, summary{
+					Name:    group.Name(),
+					Len:     len(names),
+					Members: names,
+				})
+			}
+			respond(w, summaries)
+			return
+		}
+
+		for _, group := range groups {
+			if group.Name() != name {
+				continue
+			}
+
+			switch view {
+			case "":
+				respond(w, group)
+			case "stats":
+				respond(w, group.Stats())
+			case "events":
+				respond(w, events{group})
+			default:
+				http.NotFound(w, r)
+			}
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+// events encodes the recent events of a group.
+type events struct {
+	group Inspector
+}
+
+// MarshalJSON encodes the group's recent events.
+func (this events) MarshalJSON() ([]byte, error) {
+	return this.group.MarshalEventsJSON()
+}
+
+// respond writes a value as a JSON response.
+func respond(w http.ResponseWriter, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
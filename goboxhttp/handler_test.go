@@ -0,0 +1,91 @@
+package goboxhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martinjungblut/gobox/sharef"
+)
+
+func get(handler http.Handler, path string, t *testing.T) (int, string) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return recorder.Code, string(body)
+}
+
+func Test_Handler(t *testing.T) {
+	numbers := sharef.NewGroup[int]("numbers")
+	numbers.New("b", 2)
+	numbers.New("a", 1)
+
+	words := sharef.NewGroup[string]("words")
+	words.New("greeting", "hello")
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/gobox/", http.StripPrefix("/debug/gobox", Handler(&numbers, &words)))
+
+	code, body := get(mux, "/debug/gobox/", t)
+	expected := `[{"name":"numbers","len":2,"members":["a","b"]},{"name":"words","len":1,"members":["greeting"]}]`
+	if code != http.StatusOK || body != expected {
+		t.Errorf("Unexpected listing: '%d', '%s'.", code, body)
+	}
+
+	code, body = get(mux, "/debug/gobox/numbers", t)
+	if code != http.StatusOK || body != `{"a":1,"b":2}` {
+		t.Errorf("Unexpected values: '%d', '%s'.", code, body)
+	}
+
+	code, _ = get(mux, "/debug/gobox/missing", t)
+	if code != http.StatusNotFound {
+		t.Errorf("Expected status 404, but got: '%d'.", code)
+	}
+}
+
+func Test_Handler_Stats_And_Events(t *testing.T) {
+	numbers := sharef.NewGroup[int]("numbers")
+	numbers.RetainEvents(1)
+
+	a := numbers.New("a", 1)
+	numbers.New("b", 2)
+	a.Swap(func(current *int) *int {
+		value := *current + 1
+		return &value
+	})
+	a.Swap(func(*int) *int { return nil })
+
+	handler := Handler(&numbers)
+
+	code, body := get(handler, "/numbers/stats", t)
+	expected := `{"a":{"writes":2,"alive":false},"b":{"writes":0,"alive":true}}`
+	if code != http.StatusOK || body != expected {
+		t.Errorf("Unexpected stats: '%d', '%s'.", code, body)
+	}
+
+	code, body = get(handler, "/numbers/events", t)
+	if code != http.StatusOK || !strings.HasPrefix(body, `[{"sharef":"a","sequence":2,"time":`) || !strings.HasSuffix(body, `"previous":2,"current":null}]`) {
+		t.Errorf("Unexpected events: '%d', '%s'.", code, body)
+	}
+
+	code, _ = get(handler, "/numbers/missing", t)
+	if code != http.StatusNotFound {
+		t.Errorf("Expected status 404, but got: '%d'.", code)
+	}
+}
+
+func Test_Handler_Method_Not_Allowed(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, but got: '%d'.", recorder.Code)
+	}
+}
@@ -0,0 +1,88 @@
+package gobox
+
+import "context"
+
+// CtxLocker is a lock whose acquisition can be abandoned through a
+// context, so every blocking point guarded by it can be canceled.
+type CtxLocker interface {
+	// LockCtx acquires the lock, blocking until it is available;
+	// It returns the context's error, without acquiring the lock, if
+	// the context is done first.
+	LockCtx(ctx context.Context) error
+	// Unlock releases the lock.
+	Unlock()
+}
+
+// CtxMutex is a mutual exclusion lock implementing both CtxLocker and
+// sync.Locker, so it can be handed to APIs expecting either; copies of
+// a CtxMutex always refer to the same lock.
+type CtxMutex struct {
+	slot chan struct{}
+}
+
+// NewCtxMutex() creates a new, unlocked CtxMutex.
+func NewCtxMutex() CtxMutex {
+	return CtxMutex{
+		slot: make(chan struct{}, 1),
+	}
+}
+
+// LockCtx acquires the CtxMutex, blocking until it is available;
+// It returns the context's error, without acquiring the CtxMutex, if
+// the context is done first;
+// LockCtx *panics* if:
+// 1: the CtxMutex was not created through NewCtxMutex() (zero value).
+func (this CtxMutex) LockCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case this.acquire() <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lock acquires the CtxMutex, blocking until it is available;
+// Lock *panics* if:
+// 1: the CtxMutex was not created through NewCtxMutex() (zero value).
+func (this CtxMutex) Lock() {
+	this.acquire() <- struct{}{}
+}
+
+// TryLock acquires the CtxMutex if it is available, without waiting;
+// It returns whether the CtxMutex was acquired;
+// TryLock *panics* if:
+// 1: the CtxMutex was not created through NewCtxMutex() (zero value).
+func (this CtxMutex) TryLock() bool {
+	select {
+	case this.acquire() <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Unlock releases the CtxMutex;
+// Unlock *panics* if:
+// 1: the CtxMutex was not created through NewCtxMutex() (zero value);
+// 2: the CtxMutex is not locked.
+func (this CtxMutex) Unlock() {
+	select {
+	case <-this.acquire():
+	default:
+		panic("Invalid state: CtxMutex is not locked.")
+	}
+}
+
+// acquire returns the CtxMutex's slot, *panicking* if the CtxMutex was
+// not created through NewCtxMutex() (zero value).
+func (this CtxMutex) acquire() chan struct{} {
+	if this.slot == nil {
+		panic("Invalid state: CtxMutex was not created through NewCtxMutex().")
+	}
+
+	return this.slot
+}
@@ -0,0 +1,86 @@
+package gobox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_CtxMutex_LockCtx(t *testing.T) {
+	mutex := NewCtxMutex()
+
+	if err := mutex.LockCtx(context.Background()); err != nil {
+		t.Fatalf("Expected no error, but got: '%v'.", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := mutex.LockCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+
+	mutex.Unlock()
+	if err := mutex.LockCtx(context.Background()); err != nil {
+		t.Errorf("Expected no error once unlocked, but got: '%v'.", err)
+	}
+}
+
+func Test_CtxMutex_LockCtx_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mutex := NewCtxMutex()
+	if err := mutex.LockCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but got: '%v'.", err)
+	}
+
+	mutex.Lock()
+	mutex.Unlock()
+}
+
+func Test_CtxMutex_TryLock(t *testing.T) {
+	mutex := NewCtxMutex()
+
+	if !mutex.TryLock() {
+		t.Fatal("TryLock should have acquired an unlocked CtxMutex.")
+	}
+	if mutex.TryLock() {
+		t.Fatal("TryLock should have failed on a locked CtxMutex.")
+	}
+	mutex.Unlock()
+}
+
+func Test_CtxMutex_Exclusion(t *testing.T) {
+	var locker sync.Locker = NewCtxMutex()
+	var wg sync.WaitGroup
+	count := 0
+
+	for index := 0; index < 64; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cycle := 0; cycle < 100; cycle++ {
+				locker.Lock()
+				count++
+				locker.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count != 6400 {
+		t.Errorf("Expected '6400', but got: '%d'.", count)
+	}
+}
+
+func Test_CtxMutex_Misuse_Panics(t *testing.T) {
+	for _, body := range []func(){
+		func() { NewCtxMutex().Unlock() },
+		func() { CtxMutex{}.Lock() },
+	} {
+		AssertPanic(body, "Misuse should have caused a panic.", t)
+	}
+}
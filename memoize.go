@@ -0,0 +1,45 @@
+package gobox
+
+import "sync"
+
+// Memoize() returns a function which caches the results of the given
+// one, per key, and is safe for concurrent use;
+// Concurrent calls for a key which is not cached yet collapse into a
+// single invocation of the given function, whose result is shared by
+// all of them;
+// Errors are not cached: once a call fails, the next call for the
+// same key invokes the given function again; A panicking call fails
+// with an error wrapping ErrPanicked, and isn't cached either;
+// Memoize *panics* if:
+// 1: the function is nil.
+func Memoize[K comparable, V any](f func(K) (V, error)) func(K) (V, error) {
+	if f == nil {
+		panic("Invalid state: function is nil.")
+	}
+
+	mutex := sync.Mutex{}
+	cache := make(map[K]Delayed[V])
+
+	return func(key K) (V, error) {
+		mutex.Lock()
+		delayed, found := cache[key]
+		if !found {
+			delayed = Delay(func() (V, error) {
+				return f(key)
+			})
+			cache[key] = delayed
+		}
+		mutex.Unlock()
+
+		value, err := delayed.Force()
+		if err != nil {
+			mutex.Lock()
+			if current, found := cache[key]; found && current.state == delayed.state {
+				delete(cache, key)
+			}
+			mutex.Unlock()
+		}
+
+		return value, err
+	}
+}
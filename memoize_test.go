@@ -0,0 +1,105 @@
+package gobox
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Memoize_Caches_Per_Key(t *testing.T) {
+	calls := make(map[int]int)
+
+	square := Memoize(func(key int) (int, error) {
+		calls[key]++
+		return key * key, nil
+	})
+
+	for index := 0; index < 3; index++ {
+		for key := 1; key <= 3; key++ {
+			if value, err := square(key); err != nil || value != key*key {
+				t.Errorf("Expected '%d', but got: '%d', '%v'.", key*key, value, err)
+			}
+		}
+	}
+
+	for key := 1; key <= 3; key++ {
+		if calls[key] != 1 {
+			t.Errorf("Key '%d' should have been computed once, but was computed '%d' times.", key, calls[key])
+		}
+	}
+}
+
+func Test_Memoize_Single_Flight(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	slow := Memoize(func(key string) (string, error) {
+		calls.Add(1)
+		<-release
+		return key, nil
+	})
+
+	wg := sync.WaitGroup{}
+	wg.Add(50)
+	for index := 0; index < 50; index++ {
+		go func() {
+			defer wg.Done()
+
+			if value, _ := slow("key"); value != "key" {
+				t.Errorf("Expected 'key', but got: '%s'.", value)
+			}
+		}()
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("Function should have been called once, but was called '%d' times.", calls.Load())
+	}
+}
+
+func Test_Memoize_Does_Not_Cache_Errors(t *testing.T) {
+	errBoom := errors.New("boom")
+	calls := 0
+
+	flaky := Memoize(func(key int) (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errBoom
+		}
+		return key, nil
+	})
+
+	if _, err := flaky(1); !errors.Is(err, errBoom) {
+		t.Errorf("Expected the function's error, but got: '%v'.", err)
+	}
+
+	if value, err := flaky(1); err != nil || value != 1 {
+		t.Errorf("Expected '1', but got: '%d', '%v'.", value, err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Function should have been called twice, but was called '%d' times.", calls)
+	}
+}
+
+func Test_Memoize_Does_Not_Cache_Panics(t *testing.T) {
+	calls := 0
+
+	flaky := Memoize(func(key int) (int, error) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return key, nil
+	})
+
+	if _, err := flaky(1); !errors.Is(err, ErrPanicked) {
+		t.Errorf("Expected ErrPanicked, but got: '%v'.", err)
+	}
+
+	if value, err := flaky(1); err != nil || value != 1 {
+		t.Errorf("Expected '1', but got: '%d', '%v'.", value, err)
+	}
+}
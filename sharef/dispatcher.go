@@ -0,0 +1,193 @@
+package sharef
+
+import "sync"
+
+// OverflowPolicy determines what happens when an event is dispatched
+// while an asynchronous Group's queue is full.
+type OverflowPolicy int
+
+const (
+	// Block makes the writer wait until the queue has room for the
+	// event.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest queued event to make room for
+	// the new one.
+	DropOldest
+	// DropNewest discards the new event, keeping the queue as is.
+	DropNewest
+	// Conflate collapses the queued events of each Sharef into a
+	// single one, so a slow subscriber always receives the latest
+	// value of each Sharef, without blocking writers nor dropping
+	// arbitrary events; A conflated event carries the Previous value
+	// of the oldest collapsed event and the Current value of the
+	// newest one;
+	// The queue's capacity then bounds the number of distinct Sharefs
+	// with pending events, beyond which writers wait.
+	Conflate
+)
+
+// dispatcher delivers events through a bounded queue, consumed by a
+// single goroutine, so events are delivered in the order they were
+// queued.
+type dispatcher[T any] struct {
+	queue   chan ReadWriteEvent[T]
+	policy  OverflowPolicy
+	deliver func(ReadWriteEvent[T])
+	latest  map[string]ReadWriteEvent[T]
+
+	lock    sync.RWMutex
+	closed  bool
+	mutex   sync.Mutex
+	drained *sync.Cond
+	pending int
+	done    chan struct{}
+}
+
+// newDispatcher creates a dispatcher and starts its consumer
+// goroutine.
+func newDispatcher[T any](capacity int, policy OverflowPolicy, deliver func(ReadWriteEvent[T])) *dispatcher[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	this := &dispatcher[T]{
+		queue:   make(chan ReadWriteEvent[T], capacity),
+		policy:  policy,
+		deliver: deliver,
+		latest:  make(map[string]ReadWriteEvent[T]),
+		done:    make(chan struct{}),
+	}
+	this.drained = sync.NewCond(&this.mutex)
+
+	go func() {
+		defer close(this.done)
+
+		for event := range this.queue {
+			if this.policy == Conflate {
+				event = this.collect(event.SharefName)
+			}
+			this.deliver(event)
+			this.settle()
+		}
+	}()
+
+	return this
+}
+
+// dispatch queues an event according to the dispatcher's overflow
+// policy; Once the dispatcher is closed, events are delivered
+// synchronously instead.
+func (this *dispatcher[T]) dispatch(event ReadWriteEvent[T]) {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+
+	if this.closed {
+		this.deliver(event)
+		return
+	}
+
+	switch this.policy {
+	case DropNewest:
+		this.track()
+		select {
+		case this.queue <- event:
+		default:
+			this.settle()
+		}
+	case Conflate:
+		if this.conflate(event) {
+			this.queue <- event
+		}
+	case DropOldest:
+		this.track()
+		for {
+			select {
+			case this.queue <- event:
+				return
+			default:
+			}
+
+			select {
+			case <-this.queue:
+				this.settle()
+			default:
+			}
+		}
+	default:
+		this.track()
+		this.queue <- event
+	}
+}
+
+// conflate merges an event into the pending event of its Sharef;
+// It returns whether there was no pending event, in which case the
+// event must be queued.
+func (this *dispatcher[T]) conflate(event ReadWriteEvent[T]) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if pending, found := this.latest[event.SharefName]; found {
+		pending.Current = event.Current
+		this.latest[event.SharefName] = pending
+		return false
+	}
+
+	this.latest[event.SharefName] = event
+	this.pending++
+	return true
+}
+
+// collect removes and returns the pending event of a Sharef.
+func (this *dispatcher[T]) collect(name string) ReadWriteEvent[T] {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	event := this.latest[name]
+	delete(this.latest, name)
+	return event
+}
+
+// flush blocks until every queued event has been delivered or
+// dropped.
+func (this *dispatcher[T]) flush() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for this.pending > 0 {
+		this.drained.Wait()
+	}
+}
+
+// close stops accepting events, and waits for the queued ones to be
+// delivered; Calling it more than once has no effect.
+func (this *dispatcher[T]) close() {
+	this.lock.Lock()
+	if this.closed {
+		this.lock.Unlock()
+		return
+	}
+	this.closed = true
+	close(this.queue)
+	this.lock.Unlock()
+
+	<-this.done
+}
+
+// track accounts for an event about to be queued.
+func (this *dispatcher[T]) track() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.pending++
+}
+
+// settle accounts for a queued event which was delivered or dropped.
+func (this *dispatcher[T]) settle() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.pending--
+	if this.pending <= 0 {
+		this.drained.Broadcast()
+	}
+}
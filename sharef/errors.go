@@ -0,0 +1,22 @@
+package sharef
+
+import "errors"
+
+var (
+	// ErrPointerValue is reported when a pointer is provided as a
+	// Sharef's value.
+	ErrPointerValue = errors.New("invalid state: pointer was provided")
+
+	// ErrNilState is reported when operating on a Sharef which holds
+	// no value, either because it is dead or because its value was
+	// never originally provided (zero value).
+	ErrNilState = errors.New("invalid state: value is nil")
+
+	// ErrMalformedData is reported when decoding data which was not
+	// produced by the expected Codec.
+	ErrMalformedData = errors.New("invalid state: malformed data")
+
+	// ErrNoMigration is reported when decoding data encoded with a
+	// schema version which cannot be migrated to the current one.
+	ErrNoMigration = errors.New("invalid state: no migration path")
+)
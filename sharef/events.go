@@ -0,0 +1,118 @@
+package sharef
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Stats describes the activity of a Sharef within a Group.
+type Stats struct {
+	// Writes is the number of read-write operations performed on the
+	// Sharef, i.e. the Sequence of its latest ReadWriteEvent.
+	Writes uint64 `json:"writes"`
+	// Alive is whether the Sharef holds a value.
+	Alive bool `json:"alive"`
+}
+
+// Stats returns the Stats of every Sharef created within the Group,
+// indexed by name.
+func (this *Group[T]) Stats() map[string]Stats {
+	this.check()
+
+	this.members.mutex.RLock()
+	defer this.members.mutex.RUnlock()
+
+	stats := make(map[string]Stats, len(this.members.sharefs))
+	for name, sharedref := range this.members.sharefs {
+		stats[name] = Stats{
+			Writes: sharedref.core.member.sequence.Load(),
+			Alive:  sharedref.IsAlive(),
+		}
+	}
+
+	return stats
+}
+
+// retention holds the events retained by a Group, along with the
+// function that stops retaining them.
+type retention[T any] struct {
+	events      history[ReadWriteEvent[T]]
+	unsubscribe func()
+}
+
+// RetainEvents makes the Group retain its latest read-write events, up
+// to capacity, so they can be inspected through Events(); Retaining
+// again replaces the previously retained events;
+// It returns a function that stops retaining events, and discards the
+// retained ones.
+// *Note*: values are retained as shallow copies.
+func (this *Group[T]) RetainEvents(capacity int) func() {
+	this.check()
+
+	current := &retention[T]{}
+	current.events.resize(capacity)
+	current.unsubscribe = this.OnReadWrite(func(event ReadWriteEvent[T]) {
+		event.Previous = duplicate(event.Previous)
+		event.Current = duplicate(event.Current)
+		current.events.record(event)
+	})
+
+	if previous := this.retained.Swap(current); previous != nil {
+		previous.unsubscribe()
+	}
+
+	return func() {
+		if this.retained.CompareAndSwap(current, nil) {
+			current.unsubscribe()
+		}
+	}
+}
+
+// Events returns a copy of the events retained by the Group, oldest
+// first; It is empty unless the Group retains events.
+func (this *Group[T]) Events() []ReadWriteEvent[T] {
+	this.check()
+
+	if current := this.retained.Load(); current != nil {
+		return current.events.list()
+	}
+
+	return []ReadWriteEvent[T]{}
+}
+
+// eventJSON is the JSON encoding of a ReadWriteEvent; Callers are
+// encoded as "function (file:line)".
+type eventJSON[T any] struct {
+	SharefName string    `json:"sharef"`
+	Sequence   uint64    `json:"sequence"`
+	Time       time.Time `json:"time"`
+	Previous   *T        `json:"previous"`
+	Current    *T        `json:"current"`
+	Callers    []string  `json:"callers,omitempty"`
+}
+
+// MarshalEventsJSON encodes the events retained by the Group as an
+// array, oldest first.
+func (this *Group[T]) MarshalEventsJSON() ([]byte, error) {
+	events := this.Events()
+
+	encoded := make([]eventJSON[T], 0, len(events))
+	for _, event := range events {
+		callers := make([]string, 0, len(event.Callers))
+		for _, frame := range event.Callers {
+			callers = append(callers, frame.Function+" ("+frame.File+":"+strconv.Itoa(frame.Line)+")")
+		}
+
+		encoded = append(encoded, eventJSON[T]{
+			SharefName: event.SharefName,
+			Sequence:   event.Sequence,
+			Time:       event.Time,
+			Previous:   event.Previous,
+			Current:    event.Current,
+			Callers:    callers,
+		})
+	}
+
+	return json.Marshal(encoded)
+}
@@ -1,58 +1,350 @@
 package sharef
 
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 // ReadWriteEvent represents the information associated with a
 // read-write event within a Group;
 // It includes details such as the group name, Sharef name, previous
-// value, and current value involved in the event.
+// value, and current value involved in the event, along with the
+// stack of the caller which performed it, if the Group captures
+// callers.
 type ReadWriteEvent[T any] struct {
 	GroupName  string
 	SharefName string
 	Previous   *T
 	Current    *T
+	Callers    []runtime.Frame
+	// Sequence numbers the Sharef's read-write operations, starting at
+	// 1 and increasing by 1 with each one, so events can be correlated
+	// across subscribers and logs.
+	Sequence uint64
+	// Time is the wall-clock time at which the operation completed.
+	Time time.Time
+}
+
+// DeathEvent represents the information associated with the death of
+// a Sharef within a Group, i.e. a nil write to a live Sharef;
+// It includes the group name, the Sharef name, the last value the
+// Sharef held, and the stack of the caller which killed it, along with
+// the Sequence and Time of the killing write, as in ReadWriteEvent.
+type DeathEvent[T any] struct {
+	GroupName  string
+	SharefName string
+	Last       T
+	Callers    []runtime.Frame
+	Sequence   uint64
+	Time       time.Time
 }
 
 // Group represents a collection of Sharef instances that are
 // associated and can be used to perform group-level operations;
 // It allows the creation of named Sharef instances within the group,
-// and provides a mechanism to set a callback function to be invoked
-// on every read-write operation within the group.
+// keeps track of them so they can be looked up by name afterwards,
+// and provides a mechanism to register callback functions to be
+// invoked on every read-write operation within the group;
+// Groups must be created through NewGroup(); Every method *panics* if
+// called on a Group which was not (zero value).
 type Group[T any] struct {
 	name        string
-	onReadWrite func(ReadWriteEvent[T])
+	subscribers *subscribers[func(ReadWriteEvent[T])]
+	members     *members[T]
+	async       *atomic.Pointer[dispatcher[T]]
+	depth       *atomic.Int32
+	mourners    *atomic.Int32
+	retained    *atomic.Pointer[retention[T]]
 }
 
+// members is the registry of the Sharef instances created within a
+// Group, indexed by name.
+type members[T any] struct {
+	mutex   sync.RWMutex
+	sharefs map[string]Sharef[T]
+}
+
+// NewGroup() creates a new, empty Group with the given name.
 func NewGroup[T any](name string) Group[T] {
 	return Group[T]{
-		name: name,
+		name:        name,
+		subscribers: &subscribers[func(ReadWriteEvent[T])]{},
+		members:     &members[T]{sharefs: make(map[string]Sharef[T])},
+		async:       &atomic.Pointer[dispatcher[T]]{},
+		depth:       &atomic.Int32{},
+		mourners:    &atomic.Int32{},
+		retained:    &atomic.Pointer[retention[T]]{},
 	}
 }
 
+// New creates a new named Sharef within the Group;
+// If the name is already in use within the Group, the new Sharef
+// replaces the previous one in the Group's registry, so Get() and
+// Names() only see the latest; The previous Sharef keeps working,
+// and its writes are still reported to the Group;
+// New *panics* if:
+// 1: a pointer is provided as its value.
 func (this *Group[T]) New(name string, value T) Sharef[T] {
-	sharedref := New(value)
-	sharedref.name = &name
-	sharedref.group = this
+	return this.adopt(name, New(value), true)
+}
+
+// Name returns the Group's name.
+func (this *Group[T]) Name() string {
+	this.check()
+
+	return this.name
+}
+
+// check *panics* if the Group was not created through NewGroup() (zero
+// value); Its state is initialized there once, rather than lazily by
+// whichever method is called first, which would race.
+func (this *Group[T]) check() {
+	if this.members == nil {
+		panic("Invalid state: Group was not created through NewGroup().")
+	}
+}
+
+// adopt registers a Sharef within the Group under the given name,
+// unless the name is already in use and replace is false;
+// It returns the Sharef registered under the name.
+func (this *Group[T]) adopt(name string, sharedref Sharef[T], replace bool) Sharef[T] {
+	this.check()
+
+	this.members.mutex.Lock()
+	defer this.members.mutex.Unlock()
+
+	if existing, exists := this.members.sharefs[name]; exists && !replace {
+		return existing
+	}
+
+	sharedref.core.member = &member[T]{name: name, group: this}
+	this.members.sharefs[name] = sharedref
 	return sharedref
 }
 
-// OnReadWrite sets a callback function to be invoked on every
-// read-write operation within the Group.
-func (this *Group[T]) OnReadWrite(callback func(ReadWriteEvent[T])) {
-	this.onReadWrite = callback
+// Get returns the Sharef created within the Group under the given
+// name, and whether it was found.
+func (this *Group[T]) Get(name string) (Sharef[T], bool) {
+	this.check()
+
+	this.members.mutex.RLock()
+	defer this.members.mutex.RUnlock()
+
+	sharedref, found := this.members.sharefs[name]
+	return sharedref, found
 }
 
-// doReadWrite invokes the OnReadWrite callback function, if set, with
-// the information about a read-write event within the Group;
-// It provides details such as the group name, Sharef name, previous
-// value, and current value;
-// If no callback is set, this method has no effect.
-func (this *Group[T]) doReadWrite(name string, previous *T, current *T) {
-	if this.onReadWrite != nil {
-		event := ReadWriteEvent[T]{
-			GroupName:  this.name,
-			SharefName: name,
-			Previous:   previous,
-			Current:    current,
+// Names returns the names of every Sharef created within the Group,
+// sorted in ascending order.
+func (this *Group[T]) Names() []string {
+	this.check()
+
+	this.members.mutex.RLock()
+	defer this.members.mutex.RUnlock()
+
+	names := make([]string, 0, len(this.members.sharefs))
+	for name := range this.members.sharefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Len returns the number of Sharef instances created within the
+// Group.
+func (this *Group[T]) Len() int {
+	this.check()
+
+	this.members.mutex.RLock()
+	defer this.members.mutex.RUnlock()
+
+	return len(this.members.sharefs)
+}
+
+// OnReadWrite registers a callback function to be invoked on every
+// read-write operation within the Group;
+// Any number of callbacks may be registered, and each one receives
+// every event; Events are delivered synchronously, in registration
+// order, so the events of a given Sharef always arrive in the order
+// its writes happened;
+// It returns a function that unregisters the callback.
+func (this *Group[T]) OnReadWrite(callback func(ReadWriteEvent[T])) func() {
+	this.check()
+
+	return this.subscribers.add(callback)
+}
+
+// OnReadWriteWhere registers a callback function to be invoked on the
+// read-write operations within the Group that satisfy the given
+// predicate, e.g. the ones on a given Sharef, or the ones killing a
+// Sharef; Events are delivered like OnReadWrite()'s, and the predicate
+// is evaluated right before delivering each one;
+// It returns a function that unregisters the callback.
+func (this *Group[T]) OnReadWriteWhere(predicate func(ReadWriteEvent[T]) bool, callback func(ReadWriteEvent[T])) func() {
+	return this.OnReadWrite(func(event ReadWriteEvent[T]) {
+		if predicate(event) {
+			callback(event)
+		}
+	})
+}
+
+// OnDeath registers a callback function to be invoked whenever one of
+// the Group's Sharefs dies, i.e. whenever nil is written to a live
+// Sharef; Events are delivered like OnReadWrite()'s;
+// While any such callback is registered, the stack of the caller
+// which killed the Sharef is always captured, up to the depth set
+// through CaptureCallers(), or 32 frames if the Group doesn't capture
+// callers otherwise; It is then exposed through the Callers field of
+// the corresponding ReadWriteEvent as well;
+// It returns a function that unregisters the callback.
+func (this *Group[T]) OnDeath(callback func(DeathEvent[T])) func() {
+	unsubscribe := this.OnReadWriteWhere(func(event ReadWriteEvent[T]) bool {
+		return event.Previous != nil && event.Current == nil
+	}, func(event ReadWriteEvent[T]) {
+		callback(DeathEvent[T]{
+			GroupName:  event.GroupName,
+			SharefName: event.SharefName,
+			Last:       *event.Previous,
+			Callers:    event.Callers,
+			Sequence:   event.Sequence,
+			Time:       event.Time,
+		})
+	})
+	this.mourners.Add(1)
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			this.mourners.Add(-1)
+		})
+	}
+}
+
+// Async makes the Group deliver its events asynchronously, through a
+// queue holding up to capacity events, so slow callbacks don't slow
+// down writers; The policy determines what happens when an event is
+// dispatched while the queue is full;
+// Events are still delivered one at a time, in the order they were
+// queued;
+// It returns a function that delivers the queued events and switches
+// the Group back to synchronous delivery.
+// *Note*: with the Block policy, callbacks must not write to the
+// Group's Sharefs, as a full queue would then deadlock;
+// *Note*: by the time an event is delivered, the values it points to
+// may have been mutated by subsequent Do() calls.
+func (this *Group[T]) Async(capacity int, policy OverflowPolicy) func() {
+	this.check()
+
+	current := newDispatcher(capacity, policy, this.deliver)
+	if previous := this.async.Swap(current); previous != nil {
+		previous.close()
+	}
+
+	return func() {
+		if this.async.CompareAndSwap(current, nil) {
+			current.close()
 		}
-		this.onReadWrite(event)
+	}
+}
+
+// Flush blocks until every event queued by the Group has been
+// delivered or dropped;
+// If the Group delivers its events synchronously, this method has no
+// effect.
+func (this *Group[T]) Flush() {
+	this.check()
+
+	if current := this.async.Load(); current != nil {
+		current.flush()
+	}
+}
+
+// CaptureCallers makes the Group capture up to depth frames of the
+// caller's stack on every read-write operation within it, exposed
+// through the Callers field of its events; A depth lower than 1 stops
+// capturing.
+// *Note*: capturing callers is expensive, it is meant for debugging.
+func (this *Group[T]) CaptureCallers(depth int) {
+	this.check()
+
+	if depth < 0 {
+		depth = 0
+	}
+	this.depth.Store(int32(depth))
+}
+
+// deathDepth is the number of frames captured for a death when the
+// Group doesn't capture callers otherwise.
+const deathDepth = 32
+
+// callers returns up to depth frames of the stack of the caller which
+// invoked Do(), DoE() or Swap() on one of the Group's Sharefs, or up
+// to deathDepth frames if the operation killed the Sharef and an
+// OnDeath callback is registered;
+// It must be called directly by doReadWrite().
+func (this *Group[T]) callers(dying bool) []runtime.Frame {
+	depth := this.depth.Load()
+	if depth == 0 && dying && this.mourners.Load() > 0 {
+		depth = deathDepth
+	}
+	if depth == 0 {
+		return nil
+	}
+
+	// Skips runtime.Callers, callers, doReadWrite, commit, do or swap,
+	// and Do, DoE or Swap.
+	pcs := make([]uintptr, depth)
+	pcs = pcs[:runtime.Callers(6, pcs)]
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	callers := make([]runtime.Frame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		callers = append(callers, frame)
+		if !more {
+			break
+		}
+	}
+
+	return callers
+}
+
+// doReadWrite dispatches a read-write event within the Group to the
+// registered OnReadWrite callback functions, if any;
+// It provides details such as the group name, Sharef name, previous
+// value, and current value, and numbers the operation within the
+// Sharef's sequence.
+func (this *Group[T]) doReadWrite(member *member[T], previous *T, current *T) {
+	event := ReadWriteEvent[T]{
+		GroupName:  this.name,
+		SharefName: member.name,
+		Previous:   previous,
+		Current:    current,
+		Callers:    this.callers(previous != nil && current == nil),
+		Sequence:   member.sequence.Add(1),
+		Time:       time.Now(),
+	}
+
+	if dispatcher := this.async.Load(); dispatcher != nil {
+		dispatcher.dispatch(event)
+		return
+	}
+
+	this.deliver(event)
+}
+
+// deliver invokes the registered OnReadWrite callback functions, in
+// registration order, with the given event.
+func (this *Group[T]) deliver(event ReadWriteEvent[T]) {
+	for _, callback := range this.subscribers.callbacks() {
+		callback(event)
 	}
 }
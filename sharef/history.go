@@ -0,0 +1,72 @@
+package sharef
+
+import (
+	"reflect"
+	"sync"
+)
+
+// history is a bounded ring of the latest values held by a Sharef;
+// A value is only recorded if it differs from the latest recorded
+// one, so reading through Do() doesn't flood the history with
+// repeated values; Only consecutive duplicates are collapsed, values
+// recorded earlier may be recorded again.
+type history[T any] struct {
+	mutex  sync.Mutex
+	values []T
+	next   int
+	count  int
+}
+
+// resize discards every recorded value and sets the maximum number of
+// values to be retained; A capacity lower than 1 disables recording.
+func (this *history[T]) resize(capacity int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	this.values = make([]T, capacity)
+	this.next = 0
+	this.count = 0
+}
+
+// record appends a value to the history, evicting the oldest one if
+// the history is full.
+func (this *history[T]) record(value T) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	capacity := len(this.values)
+	if capacity == 0 {
+		return
+	}
+
+	if this.count > 0 {
+		latest := this.values[(this.next-1+capacity)%capacity]
+		if reflect.DeepEqual(latest, value) {
+			return
+		}
+	}
+
+	this.values[this.next] = value
+	this.next = (this.next + 1) % capacity
+	if this.count < capacity {
+		this.count++
+	}
+}
+
+// list returns a copy of the recorded values, oldest first.
+func (this *history[T]) list() []T {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	capacity := len(this.values)
+	values := make([]T, 0, this.count)
+	for index := 0; index < this.count; index++ {
+		values = append(values, this.values[(this.next-this.count+index+capacity)%capacity])
+	}
+
+	return values
+}
@@ -0,0 +1,56 @@
+package sharef
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the Sharef's current value;
+// Dead Sharefs, and Sharefs whose value was never originally provided
+// (zero value), are encoded as null.
+func (this Sharef[T]) MarshalJSON() ([]byte, error) {
+	if this.core == nil {
+		return []byte("null"), nil
+	}
+
+	this.lock(context.Background(), false)
+	defer this.unlock()
+
+	return json.Marshal(this.core.state)
+}
+
+// UnmarshalJSON decodes a value and re-seeds the Sharef's state with
+// it, so all of its copies observe the decoded value;
+// A Sharef whose value was never originally provided (zero value) is
+// initialized, and null kills the Sharef; Like writes, the decoded
+// value must pass the Sharef's validators, and is recorded in its
+// history;
+// UnmarshalJSON returns:
+// 1: ErrPointerValue if T is a pointer type;
+// 2: the validator's error if the decoded value was rejected, in
+// which case the Sharef keeps its previous value.
+// *Note*: re-seeding is not a read-write operation, so no OnReadWrite
+// callbacks are invoked.
+func (this *Sharef[T]) UnmarshalJSON(data []byte) error {
+	var pointer *T
+	if err := json.Unmarshal(data, &pointer); err != nil {
+		return err
+	}
+
+	return this.reseed(pointer)
+}
+
+// MarshalJSON encodes the Group as an object mapping the name of each
+// of its Sharef instances to their current value.
+func (this Group[T]) MarshalJSON() ([]byte, error) {
+	this.check()
+
+	sharefs := make(map[string]Sharef[T])
+	this.members.mutex.RLock()
+	for name, sharedref := range this.members.sharefs {
+		sharefs[name] = sharedref
+	}
+	this.members.mutex.RUnlock()
+
+	return json.Marshal(sharefs)
+}
@@ -0,0 +1,215 @@
+package sharef
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrPosition is returned when seeking a Replayer to a position
+// outside of its Recording.
+var ErrPosition = errors.New("invalid state: position is out of range")
+
+// Step is a single write within a recorded Group;
+// Previous and Current are copies of the values involved in the
+// write, taken when it happened; They are nil if the Sharef was, or
+// became, dead.
+type Step[T any] struct {
+	SharefName string
+	Previous   *T
+	Current    *T
+}
+
+// Recording is the ordered stream of writes within a Group, along
+// with the values its Sharefs held when recording started.
+type Recording[T any] struct {
+	mutex   sync.Mutex
+	initial map[string]*T
+	steps   []Step[T]
+}
+
+// Steps returns a copy of the recorded writes, in the order they
+// happened.
+func (this *Recording[T]) Steps() []Step[T] {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return append([]Step[T]{}, this.steps...)
+}
+
+// Record starts recording every write within the Group;
+// It returns the Recording, and a function that stops recording;
+// Recording subscribes to the Group before taking the initial values,
+// so no write is missed; A write racing with Record() may however be
+// reflected in the initial values and recorded as a step as well, in
+// which case replaying it may briefly restore an older value, so the
+// Group should be quiescent while recording starts for an exact
+// replay.
+// *Note*: values are recorded as shallow copies.
+func (this *Group[T]) Record() (*Recording[T], func()) {
+	recording := &Recording[T]{
+		initial: make(map[string]*T),
+	}
+
+	stop := this.OnReadWrite(func(event ReadWriteEvent[T]) {
+		recording.mutex.Lock()
+		defer recording.mutex.Unlock()
+
+		recording.steps = append(recording.steps, Step[T]{
+			SharefName: event.SharefName,
+			Previous:   duplicate(event.Previous),
+			Current:    duplicate(event.Current),
+		})
+	})
+
+	for _, name := range this.Names() {
+		if sharedref, found := this.Get(name); found {
+			initial := sharedref.snapshot()
+
+			recording.mutex.Lock()
+			recording.initial[name] = initial
+			recording.mutex.Unlock()
+		}
+	}
+
+	return recording, stop
+}
+
+// Replayer re-applies a Recording into a Group, one write at a time;
+// It starts at position 0, before the first recorded write, with the
+// Group holding the values recorded when recording started.
+// *Note*: replaying is not a read-write operation, so no OnReadWrite
+// callbacks are invoked.
+type Replayer[T any] struct {
+	mutex    sync.Mutex
+	group    *Group[T]
+	initial  map[string]*T
+	steps    []Step[T]
+	position int
+}
+
+// NewReplayer() creates a new Replayer of a Recording into a Group,
+// which should be a fresh one; Sharefs missing from the Group are
+// created as needed.
+func NewReplayer[T any](recording *Recording[T], group *Group[T]) *Replayer[T] {
+	recording.mutex.Lock()
+	defer recording.mutex.Unlock()
+
+	this := &Replayer[T]{
+		group:   group,
+		initial: recording.initial,
+		steps:   append([]Step[T]{}, recording.steps...),
+	}
+	this.Seek(0)
+
+	return this
+}
+
+// Position returns the number of recorded writes applied so far.
+func (this *Replayer[T]) Position() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return this.position
+}
+
+// Len returns the number of recorded writes.
+func (this *Replayer[T]) Len() int {
+	return len(this.steps)
+}
+
+// Step applies the next recorded write;
+// It returns whether a write was applied, which is false once every
+// recorded write has been applied.
+func (this *Replayer[T]) Step() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.position >= len(this.steps) {
+		return false
+	}
+
+	step := this.steps[this.position]
+	this.apply(step.SharefName, step.Current)
+	this.position++
+
+	return true
+}
+
+// Seek moves the Replayer to the given position, forwards or
+// backwards, setting every Sharef to the value it held at that point;
+// Seek returns ErrPosition if:
+// 1: the position is negative, or greater than the number of recorded
+// writes.
+func (this *Replayer[T]) Seek(position int) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if position < 0 || position > len(this.steps) {
+		return ErrPosition
+	}
+
+	values := make(map[string]*T)
+	for name, value := range this.initial {
+		values[name] = value
+	}
+
+	// Sharefs created after recording started hold the previous
+	// value of their first write until it happens.
+	for index := len(this.steps) - 1; index >= position; index-- {
+		step := this.steps[index]
+		if _, found := this.initial[step.SharefName]; !found {
+			values[step.SharefName] = step.Previous
+		}
+	}
+
+	for _, step := range this.steps[:position] {
+		values[step.SharefName] = step.Current
+	}
+
+	for name, value := range values {
+		this.apply(name, value)
+	}
+	this.position = position
+
+	return nil
+}
+
+// Play applies the remaining recorded writes, waiting for the given
+// delay before each one;
+// It returns the context's error if it is done first, which pauses
+// the replay at the current position.
+func (this *Replayer[T]) Play(ctx context.Context, delay time.Duration) error {
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if !this.Step() {
+			return nil
+		}
+	}
+}
+
+// apply sets the value of a Sharef within the Group, creating it if
+// needed.
+func (this *Replayer[T]) apply(name string, value *T) {
+	sharedref := this.group.adopt(name, Dead[T](), false)
+	sharedref.reseed(duplicate(value))
+}
+
+// duplicate returns a pointer to a shallow copy of the given value,
+// or nil.
+func duplicate[T any](pointer *T) *T {
+	if pointer == nil {
+		return nil
+	}
+
+	value := *pointer
+	return &value
+}
@@ -1,77 +1,598 @@
 package sharef
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"sync"
+	"sync/atomic"
+
+	"github.com/martinjungblut/gobox"
 )
 
 // Sharef is a shared reference; copies of a Sharef always refer to
 // the same value, so a modification to any copy implies a state
 // mutation across all copies.
 type Sharef[T any] struct {
-	state **T
-	name  *string
-	group *Group[T]
+	core *core[T]
+}
+
+// core is the state of a Sharef, shared by all of its copies;
+// Gathering it in a single struct keeps Sharefs a single word long,
+// and their construction down to two allocations: the core and the
+// value; Opt-in features are held behind pointers which stay nil
+// until enabled, so plain Sharefs stay small.
+type core[T any] struct {
+	state  *T
+	locker unlocker
+
+	member     *member[T]
+	validation *validation[T]
+	observers  atomic.Pointer[subscribers[func(previous *T, current *T)]]
+	history    atomic.Pointer[history[T]]
+	watchdog   atomic.Pointer[watchdog]
+}
+
+// member identifies a Sharef created within a Group, and numbers its
+// read-write operations.
+type member[T any] struct {
+	name     string
+	group    *Group[T]
+	sequence atomic.Uint64
+}
+
+// validation holds the validators of a Sharef created through
+// NewValidated(), along with its OnReject callback.
+type validation[T any] struct {
+	validators []func(T) error
+	onReject   func(error)
 }
 
 // New() creates a new Sharef;
 // New *panics* if:
 // 1: a pointer is provided as its value.
 func New[T any](value T) Sharef[T] {
+	instance, err := NewE(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return instance
+}
+
+// NewE() creates a new Sharef, like New(), but reports misuse as an
+// error instead of panicking;
+// NewE returns ErrPointerValue if:
+// 1: a pointer is provided as its value.
+func NewE[T any](value T) (Sharef[T], error) {
 	// Prevent pointers during runtime.
 	reflectedValue := reflect.ValueOf(value)
 	if reflectedValue.Kind() == reflect.Ptr {
-		panic("Invalid state: pointer was provided.")
+		return Sharef[T]{}, ErrPointerValue
+	}
+
+	return fromPointer(&value), nil
+}
+
+// Scalar is satisfied by the types which can never be pointers:
+// booleans, numbers and strings, and the types defined on them.
+type Scalar interface {
+	~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~complex64 | ~complex128 |
+		~string
+}
+
+// NewScalar() creates a new Sharef, like New(), but rejects pointers
+// at compile time through its type constraint, instead of inspecting
+// the value through reflection at runtime, so it never panics;
+// *Note*: construction costs about the same as New(), as it is
+// dominated by the allocation of the state shared by all copies.
+func NewScalar[T Scalar](value T) Sharef[T] {
+	return fromPointer(&value)
+}
+
+// Dead() creates a new dead Sharef, which holds no value;
+// Do() calls on a dead Sharef have no effect.
+func Dead[T any]() Sharef[T] {
+	return fromPointer[T](nil)
+}
+
+// fromPointer creates a new Sharef whose state is the given pointer.
+func fromPointer[T any](pointer *T) Sharef[T] {
+	return Sharef[T]{
+		core: &core[T]{state: pointer},
 	}
+}
 
-	pointer := &value
-	instance := Sharef[T]{
-		state: &pointer,
+// NewAtomic() creates a new Sharef that serializes its own Do() calls;
+// All copies share the same embedded mutex, so callers don't need to
+// provide one themselves;
+// NewAtomic *panics* if:
+// 1: a pointer is provided as its value.
+// *Note*: nesting Do() calls on the same atomic Sharef deadlocks.
+func NewAtomic[T any](value T) Sharef[T] {
+	return NewAtomicWith(value, &sync.Mutex{})
+}
+
+// NewAtomicWith() creates a new Sharef that serializes its own Do()
+// calls through the given locker, like NewAtomic(), so the locking
+// strategy can match the workload, e.g. a *Spinlock for extremely
+// short critical sections; A *sync.RWMutex is locked exclusively;
+// All copies share the same locker;
+// NewAtomicWith *panics* if:
+// 1: a pointer is provided as its value;
+// 2: the locker is nil.
+// *Note*: nesting Do() calls on the same atomic Sharef deadlocks.
+func NewAtomicWith[T any](value T, locker sync.Locker) Sharef[T] {
+	if locker == nil {
+		panic("Invalid state: locker is nil.")
 	}
 
+	instance := New(value)
+	instance.core.locker = locker
 	return instance
 }
 
+// NewAtomicCtx() creates a new Sharef that serializes its own Do()
+// calls through the given gobox.CtxLocker, like NewAtomicWith(), so
+// lockers which can only be acquired through LockCtx() can be used;
+// All copies share the same locker;
+// NewAtomicCtx *panics* if:
+// 1: a pointer is provided as its value;
+// 2: the locker is nil.
+// *Note*: nesting Do() calls on the same atomic Sharef deadlocks.
+func NewAtomicCtx[T any](value T, locker gobox.CtxLocker) Sharef[T] {
+	if locker == nil {
+		panic("Invalid state: locker is nil.")
+	}
+
+	instance := New(value)
+	instance.core.locker = locker
+	return instance
+}
+
+// NewValidated() creates a new Sharef whose writes must pass every
+// given validator;
+// A write rejected by any validator is discarded, the Sharef keeps
+// its previous value, and the validator's error is reported to the
+// OnReject callback, if set;
+// NewValidated *panics* if:
+// 1: a pointer is provided as its value.
+// *Note*: the initial value is not validated.
+func NewValidated[T any](value T, validators ...func(T) error) Sharef[T] {
+	instance := New(value)
+	instance.core.validation = &validation[T]{validators: validators}
+	return instance
+}
+
+// OnReject sets a callback function to be invoked whenever a write is
+// rejected by one of the Sharef's validators;
+// It has no effect on Sharefs not created through NewValidated().
+func (this Sharef[T]) OnReject(callback func(error)) {
+	if this.core != nil && this.core.validation != nil {
+		this.core.validation.onReject = callback
+	}
+}
+
 // Do applies a given function to the Sharef's value;
 // It creates a Portal for reading and writing the current and
-// modified values, executes the provided function with the Portal and
-// updates the Sharef's state based on the modifications;
+// modified values, executes the provided function with the Portal on
+// the calling goroutine and updates the Sharef's state based on the
+// modifications; If the function hands the Portal to another
+// goroutine, Do() waits for that goroutine's write;
+// If the Sharef was created through NewValidated(), the written value
+// is only committed if it passes every validator;
+// Writing nil kills the Sharef; Do() calls on a dead Sharef have no
+// effect, the provided function is not executed;
 // Do *panics* if:
 // 1: the Sharef's value was never originally provided (zero value);
-// 2: if a previous Do() call set the value to nil;
+// 2: the function writes to the Portal more than once, in which case
+// nothing is committed;
 // *Note*: Do *is not atomic*, for atomicity to be guaranteed, please use a
-// mutex;
+// mutex, or create the Sharef with NewAtomic();
 func (this Sharef[T]) Do(body func(Portal[T])) {
-	if this.state == nil || *this.state == nil {
-		panic("Invalid state: value is nil.")
+	if this.core == nil {
+		panic(ErrNilState)
 	}
 
-	reader := make(chan *T)
-	writer := make(chan *T)
-	portal := Portal[T]{
-		Reader: reader,
-		Writer: writer,
+	this.do(context.Background(), false, body)
+}
+
+// DoE applies a given function to the Sharef's value, like Do(), but
+// reports misuse and rejected writes as errors instead of panicking;
+// DoE returns:
+// 1: ErrNilState if the Sharef is dead, or its value was never
+// originally provided (zero value), in which case the provided
+// function is not executed;
+// 2: the validator's error if the written value was rejected.
+func (this Sharef[T]) DoE(body func(Portal[T])) error {
+	return this.do(context.Background(), false, body)
+}
+
+// DoCtx applies a given function to the Sharef's value, like DoE(),
+// but gives up waiting for the Sharef's locker once the context is
+// done; Only lockers implementing gobox.CtxLocker, such as
+// gobox.CtxMutex, can be abandoned while waiting, other lockers are
+// only checked against the context before waiting;
+// DoCtx returns:
+// 1: the context's error if it is done before the locker is
+// acquired, in which case the provided function is not executed;
+// 2: any error DoE() returns.
+func (this Sharef[T]) DoCtx(ctx context.Context, body func(Portal[T])) error {
+	return this.do(ctx, false, body)
+}
+
+// TryDo applies a given function to the Sharef's value, like Do(),
+// unless the Sharef's locker is held elsewhere, in which case it
+// returns immediately instead of waiting; Only lockers implementing
+// TryLock() bool, such as *sync.Mutex, *Spinlock and gobox.CtxMutex,
+// can be skipped, other lockers are waited for as usual;
+// It returns whether the function was executed, which it is not if
+// the locker is held elsewhere, or if the Sharef is dead;
+// TryDo *panics* if:
+// 1: the Sharef's value was never originally provided (zero value).
+func (this Sharef[T]) TryDo(body func(Portal[T])) bool {
+	if this.core == nil {
+		panic(ErrNilState)
 	}
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+	err := this.do(context.Background(), true, body)
+	return err != errContended && err != ErrNilState
+}
+
+// do implements Do(), DoE(), DoCtx() and TryDo(); All of them call it
+// directly, so the caller's stack is always at the same depth when
+// captured.
+func (this Sharef[T]) do(ctx context.Context, try bool, body func(Portal[T])) error {
+	if err := this.lock(ctx, try); err != nil {
+		return err
+	}
+	defer this.unlock()
+
+	if this.core.state == nil {
+		return ErrNilState
+	}
 
-	go func() {
-		body(portal)
-		wg.Done()
-	}()
+	// The Portal's channels are buffered, so the function runs on the
+	// calling goroutine: the current value is already waiting to be
+	// read, and the first write doesn't block; The spare slot lets a
+	// second write be detected instead of deadlocking the caller.
+	reader := make(chan *T, 1)
+	writer := make(chan *T, 2)
+	defer close(writer)
 
-	previous := *this.state
+	previous := this.core.state
 	reader <- previous
 	close(reader)
 
+	var unwatch func()
+	if timeout, report := this.core.watchdog.Load().settings(); report != nil {
+		unwatch = this.watch(timeout, report, goroutineID())
+		defer unwatch()
+	}
+
+	body(Portal[T]{
+		Reader: reader,
+		Writer: writer,
+	})
+
 	current := <-writer
-	*this.state = current
-	close(writer)
+	if unwatch != nil {
+		unwatch()
+	}
+	if len(writer) > 0 {
+		panic("Invalid state: Portal was written to more than once.")
+	}
+
+	return this.commit(previous, current)
+}
+
+// Swap replaces the Sharef's value with the one returned by a given
+// function, which receives the current one;
+// It behaves like Do(), except the function runs without a Portal,
+// which avoids the channels Do() needs; It is meant for hot paths;
+// Returning nil kills the Sharef; Swap() calls on a dead Sharef have
+// no effect, the provided function is not executed;
+// Swap *panics* if:
+// 1: the Sharef's value was never originally provided (zero value);
+// *Note*: Swap *is not atomic* either, unless the Sharef was created
+// with NewAtomic(); Swap() calls are not reported by the watchdog, as
+// they have no Portal to abandon.
+func (this Sharef[T]) Swap(body func(*T) *T) {
+	if this.core == nil {
+		panic(ErrNilState)
+	}
+
+	this.swap(context.Background(), false, body)
+}
+
+// SwapCtx replaces the Sharef's value, like Swap(), but gives up
+// waiting for the Sharef's locker once the context is done, like
+// DoCtx();
+// SwapCtx returns:
+// 1: ErrNilState if the Sharef is dead, or its value was never
+// originally provided (zero value), in which case the provided
+// function is not executed;
+// 2: the context's error if it is done before the locker is
+// acquired, in which case the provided function is not executed;
+// 3: the validator's error if the returned value was rejected.
+func (this Sharef[T]) SwapCtx(ctx context.Context, body func(*T) *T) error {
+	return this.swap(ctx, false, body)
+}
+
+// TrySwap replaces the Sharef's value, like Swap(), unless the
+// Sharef's locker is held elsewhere, like TryDo();
+// It returns whether the function was executed, which it is not if
+// the locker is held elsewhere, or if the Sharef is dead;
+// TrySwap *panics* if:
+// 1: the Sharef's value was never originally provided (zero value).
+func (this Sharef[T]) TrySwap(body func(*T) *T) bool {
+	if this.core == nil {
+		panic(ErrNilState)
+	}
+
+	err := this.swap(context.Background(), true, body)
+	return err != errContended && err != ErrNilState
+}
+
+// swap implements Swap(), SwapCtx() and TrySwap(), keeping the
+// caller's stack at the same depth as do() when captured.
+func (this Sharef[T]) swap(ctx context.Context, try bool, body func(*T) *T) error {
+	if err := this.lock(ctx, try); err != nil {
+		return err
+	}
+	defer this.unlock()
+
+	if this.core.state == nil {
+		return ErrNilState
+	}
+
+	previous := this.core.state
+	return this.commit(previous, body(previous))
+}
+
+// unlocker is implemented by every locker a Sharef accepts, be it a
+// sync.Locker or a gobox.CtxLocker.
+type unlocker interface {
+	Unlock()
+}
+
+// tryLocker is implemented by lockers which can be acquired without
+// waiting, such as *sync.Mutex.
+type tryLocker interface {
+	TryLock() bool
+}
+
+// errContended is returned by lock() when trying to acquire a locker
+// which is held elsewhere.
+var errContended = errors.New("invalid state: locker is held elsewhere")
+
+// lock acquires the Sharef's locker, if any, giving up once the
+// context is done when the locker implements gobox.CtxLocker, or
+// right away when trying to acquire a locker implementing TryLock();
+// It returns the context's error, or errContended, if the locker was
+// not acquired, and ErrNilState if the Sharef's value was never
+// originally provided (zero value); Callers which already ruled out
+// the zero value, and neither try nor pass a context which can be
+// done, may ignore its result.
+func (this Sharef[T]) lock(ctx context.Context, try bool) error {
+	if this.core == nil {
+		return ErrNilState
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if locker, ok := this.core.locker.(tryLocker); ok && try {
+		if !locker.TryLock() {
+			return errContended
+		}
+		return nil
+	}
+
+	switch locker := this.core.locker.(type) {
+	case gobox.CtxLocker:
+		return locker.LockCtx(ctx)
+	case sync.Locker:
+		locker.Lock()
+	}
+
+	return nil
+}
+
+// unlock releases the Sharef's locker, if any, once acquired through
+// lock().
+func (this Sharef[T]) unlock() {
+	if this.core.locker != nil {
+		this.core.locker.Unlock()
+	}
+}
+
+// commit sets the Sharef's state to the written value, unless a
+// validator rejects it, records it, and notifies the observers and
+// the Group, if any;
+// It returns the validator's error if the written value was rejected.
+func (this Sharef[T]) commit(previous *T, current *T) error {
+	err := this.validate(current)
+	if err != nil {
+		current = previous
+		if this.core.validation.onReject != nil {
+			this.core.validation.onReject(err)
+		}
+	}
+	this.core.state = current
+
+	if history := this.core.history.Load(); history != nil && err == nil && current != nil {
+		history.record(*current)
+	}
+
+	if observers := this.core.observers.Load(); observers != nil {
+		for _, callback := range observers.callbacks() {
+			callback(previous, current)
+		}
+	}
+
+	if member := this.core.member; member != nil {
+		member.group.doReadWrite(member, previous, current)
+	}
+
+	return err
+}
+
+// IsAlive returns whether the Sharef holds a value.
+func (this Sharef[T]) IsAlive() bool {
+	return !this.IsDead()
+}
+
+// IsDead returns whether the Sharef holds no value, either because it
+// was created dead, a nil value was written to it, or it was never
+// originally provided a value (zero value).
+func (this Sharef[T]) IsDead() bool {
+	return this.core == nil || this.core.state == nil
+}
+
+// OnReadWrite registers a callback function to be invoked on every
+// read-write operation on the Sharef, or any of its copies;
+// It returns a function that unregisters the callback;
+// OnReadWrite *panics* if:
+// 1: the Sharef's value was never originally provided (zero value).
+func (this Sharef[T]) OnReadWrite(callback func(previous *T, current *T)) func() {
+	if this.core == nil {
+		panic(ErrNilState)
+	}
+
+	observers := this.core.observers.Load()
+	if observers == nil {
+		this.core.observers.CompareAndSwap(nil, &subscribers[func(previous *T, current *T)]{})
+		observers = this.core.observers.Load()
+	}
+
+	return observers.add(callback)
+}
+
+// WithHistory makes the Sharef, and all of its copies, retain its
+// last n values, starting with the current one;
+// Consecutive duplicates are collapsed: a value deeply equal to the
+// latest retained one is not retained again, so reading through Do()
+// doesn't flood the history, but rewriting the same value isn't
+// recorded either; Values seen earlier are retained again once a
+// different value was retained in between, e.g. A, B, A;
+// Previously retained values are discarded; A value of n lower than 1
+// disables the history;
+// It returns the Sharef itself, so it can be chained to a constructor;
+// WithHistory *panics* if:
+// 1: the Sharef's value was never originally provided (zero value).
+// *Note*: values are retained as shallow copies.
+func (this Sharef[T]) WithHistory(n int) Sharef[T] {
+	if this.core == nil {
+		panic(ErrNilState)
+	}
+
+	this.lock(context.Background(), false)
+	defer this.unlock()
+
+	if n < 1 {
+		this.core.history.Store(nil)
+		return this
+	}
+
+	history := &history[T]{}
+	history.resize(n)
+	if this.core.state != nil {
+		history.record(*this.core.state)
+	}
+	this.core.history.Store(history)
+
+	return this
+}
+
+// History returns the values retained by the Sharef, oldest first;
+// It is empty unless WithHistory() was called.
+func (this Sharef[T]) History() []T {
+	if this.core == nil {
+		return []T{}
+	}
+
+	history := this.core.history.Load()
+	if history == nil {
+		return []T{}
+	}
+
+	return history.list()
+}
+
+// reseed replaces the Sharef's state with the given pointer, so all
+// of its copies observe it; A Sharef whose value was never originally
+// provided (zero value) is initialized, and a nil pointer kills it;
+// Like writes, the new value must pass the Sharef's validators, and is
+// recorded in its history;
+// reseed returns:
+// 1: ErrPointerValue if T is a pointer type;
+// 2: the validator's error if the new value was rejected, in which
+// case the Sharef keeps its previous value.
+func (this *Sharef[T]) reseed(pointer *T) error {
+	if pointer != nil {
+		if _, err := NewE(*pointer); err != nil {
+			return err
+		}
+	}
+
+	if this.core == nil {
+		*this = fromPointer(pointer)
+		return nil
+	}
+
+	this.lock(context.Background(), false)
+	defer this.unlock()
+
+	if err := this.validate(pointer); err != nil {
+		return err
+	}
+
+	this.core.state = pointer
+	if pointer != nil {
+		if history := this.core.history.Load(); history != nil {
+			history.record(*pointer)
+		}
+	}
+	return nil
+}
+
+// snapshot returns a pointer to a shallow copy of the Sharef's
+// current value, or nil if it is dead.
+func (this Sharef[T]) snapshot() *T {
+	if this.core == nil {
+		return nil
+	}
+
+	this.lock(context.Background(), false)
+	defer this.unlock()
+
+	return duplicate(this.core.state)
+}
+
+// validate runs the Sharef's validators against a written value,
+// returning the first error found;
+// Nil writes are not validated.
+// *Note*: in-place mutations made through the pointer read from the
+// Portal cannot be rolled back by a rejection.
+func (this Sharef[T]) validate(current *T) error {
+	if current == nil {
+		return nil
+	}
+
+	if this.core.validation == nil {
+		return nil
+	}
 
-	if this.group != nil && this.name != nil {
-		this.group.doReadWrite(*this.name, previous, current)
+	for _, validator := range this.core.validation.validators {
+		if err := validator(*current); err != nil {
+			return err
+		}
 	}
 
-	wg.Wait()
+	return nil
 }
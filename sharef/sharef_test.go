@@ -1,9 +1,17 @@
 package sharef
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/martinjungblut/gobox"
 )
 
 func AssertPanic(body func(), message string, t *testing.T) {
@@ -80,6 +88,71 @@ func Test_Sharef_New_Pointer_Panics(t *testing.T) {
 	}, "Pointer should have caused a panic.", t)
 }
 
+func Test_Sharef_NewE_Pointer_Error(t *testing.T) {
+	number := 10
+
+	if _, err := NewE(&number); !errors.Is(err, ErrPointerValue) {
+		t.Errorf("Expected ErrPointerValue, but got: '%v'.", err)
+	}
+
+	if _, err := NewE(number); err != nil {
+		t.Errorf("Expected no error, but got: '%v'.", err)
+	}
+}
+
+func Test_Sharef_NewScalar(t *testing.T) {
+	type Celsius float64
+
+	sharef := NewScalar(Celsius(21.5))
+	sharef.Swap(func(pointer *Celsius) *Celsius {
+		if *pointer != 21.5 {
+			t.Errorf("Expected '21.5', but got: '%v'.", *pointer)
+		}
+		return pointer
+	})
+}
+
+func Test_Sharef_DoE_ZeroValue_Error(t *testing.T) {
+	var sharef Sharef[int]
+
+	err := sharef.DoE(func(portal Portal[int]) {
+		t.Error("DoE() should not execute its body on a zero value.")
+	})
+
+	if !errors.Is(err, ErrNilState) {
+		t.Errorf("Expected ErrNilState, but got: '%v'.", err)
+	}
+}
+
+func Test_Sharef_DoE_Dead_Error(t *testing.T) {
+	sharef := Dead[int]()
+
+	err := sharef.DoE(func(portal Portal[int]) {
+		t.Error("DoE() should not execute its body on a dead Sharef.")
+	})
+
+	if !errors.Is(err, ErrNilState) {
+		t.Errorf("Expected ErrNilState, but got: '%v'.", err)
+	}
+}
+
+func Test_Sharef_DoE_Rejected_Error(t *testing.T) {
+	errReadOnly := errors.New("read-only")
+	sharef := NewValidated(0, func(value int) error {
+		return errReadOnly
+	})
+
+	err := sharef.DoE(func(portal Portal[int]) {
+		<-portal.Reader
+		value := 1
+		portal.Writer <- &value
+	})
+
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("Expected the validator's error, but got: '%v'.", err)
+	}
+}
+
 func Test_Sharef_Do_ZeroValue_Panics(t *testing.T) {
 	AssertPanic(func() {
 		var sharef Sharef[int]
@@ -91,7 +164,7 @@ func Test_Sharef_Do_ZeroValue_Panics(t *testing.T) {
 	}, "Zero value should have caused a panic.", t)
 }
 
-func Test_Sharef_Do_Nil_Panics(t *testing.T) {
+func Test_Sharef_Do_Nil_Kills(t *testing.T) {
 	sharef := New(0)
 
 	sharef.Do(func(portal Portal[int]) {
@@ -99,12 +172,38 @@ func Test_Sharef_Do_Nil_Panics(t *testing.T) {
 		portal.Writer <- nil
 	})
 
-	AssertPanic(func() {
-		sharef.Do(func(portal Portal[int]) {
-			ptr := <-portal.Reader
-			portal.Writer <- ptr
-		})
-	}, "Nil value should have caused a panic.", t)
+	if !sharef.IsDead() || sharef.IsAlive() {
+		t.Error("Writing nil should have killed the Sharef.")
+	}
+
+	sharef.Do(func(portal Portal[int]) {
+		t.Error("Do() should not execute its body on a dead Sharef.")
+	})
+}
+
+func Test_Sharef_Dead(t *testing.T) {
+	sharef := Dead[int]()
+
+	if !sharef.IsDead() || sharef.IsAlive() {
+		t.Error("Sharef should be dead.")
+	}
+
+	sharef.Do(func(portal Portal[int]) {
+		t.Error("Do() should not execute its body on a dead Sharef.")
+	})
+}
+
+func Test_Sharef_IsAlive(t *testing.T) {
+	sharef := New(0)
+
+	if !sharef.IsAlive() || sharef.IsDead() {
+		t.Error("Sharef should be alive.")
+	}
+
+	var zero Sharef[int]
+	if !zero.IsDead() || zero.IsAlive() {
+		t.Error("Zero value Sharef should be dead.")
+	}
 }
 
 func Test_Sharef_Do_Atomicity(t *testing.T) {
@@ -139,6 +238,350 @@ func Test_Sharef_Do_Atomicity(t *testing.T) {
 	})
 }
 
+func Test_Sharef_NewAtomic_Pointer_Panics(t *testing.T) {
+	AssertPanic(func() {
+		number := 10
+		NewAtomic(&number)
+	}, "Pointer should have caused a panic.", t)
+}
+
+func Test_Sharef_NewAtomic_Do_Atomicity(t *testing.T) {
+	cycles := 100000
+
+	sharef := NewAtomic(0)
+
+	Concurrently(cycles, func() {
+		sharef.Do(func(portal Portal[int]) {
+			pointer := <-portal.Reader
+
+			value := *pointer
+			value++
+
+			portal.Writer <- &value
+		})
+	})
+
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		value := *pointer
+
+		if value != cycles {
+			t.Fatalf("value was '%d', but should have been '%d'.", value, cycles)
+		}
+
+		portal.Writer <- pointer
+	})
+}
+
+func Test_Sharef_NewValidated_Rejects_Invalid_Writes(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	sharef := NewValidated(0, func(value int) error {
+		if value < 0 {
+			return errNegative
+		}
+		return nil
+	})
+
+	rejections := make([]error, 0)
+	sharef.OnReject(func(err error) {
+		rejections = append(rejections, err)
+	})
+
+	for _, value := range []int{5, -1, 7} {
+		written := value
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &written
+		})
+	}
+
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 7 {
+			t.Errorf("Value should be 7, but instead it was: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+
+	if len(rejections) != 1 || rejections[0] != errNegative {
+		t.Errorf("Exactly one rejection was expected, but got: '%v'.", rejections)
+	}
+}
+
+func Test_Sharef_NewValidated_Keeps_Previous_Value(t *testing.T) {
+	sharef := NewValidated(1, func(value int) error {
+		return errors.New("read-only")
+	})
+
+	sharef.Do(func(portal Portal[int]) {
+		<-portal.Reader
+		value := 2
+		portal.Writer <- &value
+	})
+
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 1 {
+			t.Errorf("Value should be 1, but instead it was: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+}
+
+func Test_Sharef_Swap(t *testing.T) {
+	sharef := NewValidated(0, func(value int) error {
+		if value < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}).WithHistory(10)
+
+	seqCurrent := make([]int, 0)
+	unsubscribe := sharef.OnReadWrite(func(previous *int, current *int) {
+		seqCurrent = append(seqCurrent, *current)
+	})
+
+	sharef.Swap(func(pointer *int) *int {
+		value := *pointer + 1
+		return &value
+	})
+	sharef.Swap(func(pointer *int) *int {
+		value := -1
+		return &value
+	})
+
+	history := sharef.History()
+	if len(history) != 2 || history[0] != 0 || history[1] != 1 {
+		t.Errorf("Unexpected history: '%v'.", history)
+	}
+
+	if len(seqCurrent) != 2 || seqCurrent[0] != 1 || seqCurrent[1] != 1 {
+		t.Errorf("Unexpected current values: '%v'.", seqCurrent)
+	}
+
+	unsubscribe()
+	sharef.Swap(func(pointer *int) *int {
+		return nil
+	})
+	if sharef.IsAlive() {
+		t.Error("Returning nil should have killed the Sharef.")
+	}
+
+	sharef.Swap(func(pointer *int) *int {
+		t.Error("Swap() should not execute its body on a dead Sharef.")
+		return pointer
+	})
+}
+
+func Test_Sharef_Swap_ZeroValue_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var sharef Sharef[int]
+
+		sharef.Swap(func(pointer *int) *int {
+			return pointer
+		})
+	}, "Zero value should have caused a panic.", t)
+}
+
+func Test_Sharef_NewAtomic_Swap_Atomicity(t *testing.T) {
+	cycles := 100000
+
+	sharef := NewAtomic(0)
+
+	Concurrently(cycles, func() {
+		sharef.Swap(func(pointer *int) *int {
+			value := *pointer + 1
+			return &value
+		})
+	})
+
+	sharef.Swap(func(pointer *int) *int {
+		if *pointer != cycles {
+			t.Fatalf("value was '%d', but should have been '%d'.", *pointer, cycles)
+		}
+		return pointer
+	})
+}
+
+func Test_Sharef_NewAtomicWith_Atomicity(t *testing.T) {
+	cycles := 10000
+
+	for _, locker := range []sync.Locker{&sync.Mutex{}, &sync.RWMutex{}, &Spinlock{}} {
+		sharef := NewAtomicWith(0, locker)
+
+		Concurrently(cycles, func() {
+			sharef.Swap(func(pointer *int) *int {
+				value := *pointer + 1
+				return &value
+			})
+		})
+
+		sharef.Swap(func(pointer *int) *int {
+			if *pointer != cycles {
+				t.Fatalf("value was '%d', but should have been '%d', with locker '%T'.", *pointer, cycles, locker)
+			}
+			return pointer
+		})
+	}
+}
+
+func Test_Sharef_NewAtomicWith_Nil_Locker_Panics(t *testing.T) {
+	AssertPanic(func() {
+		NewAtomicWith(0, nil)
+	}, "Nil locker should have caused a panic.", t)
+}
+
+func Test_Sharef_DoCtx_Abandons_Locked_CtxMutex(t *testing.T) {
+	mutex := gobox.NewCtxMutex()
+	sharef := NewAtomicWith(0, mutex)
+
+	mutex.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := sharef.DoCtx(ctx, func(portal Portal[int]) {
+		t.Error("DoCtx() should not execute its body without the locker.")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+
+	err = sharef.SwapCtx(ctx, func(pointer *int) *int {
+		t.Error("SwapCtx() should not execute its body without the locker.")
+		return pointer
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+
+	mutex.Unlock()
+	err = sharef.DoCtx(context.Background(), func(portal Portal[int]) {
+		value := *<-portal.Reader + 1
+		portal.Writer <- &value
+	})
+	if err != nil {
+		t.Errorf("Expected no error, but got: '%v'.", err)
+	}
+	if history := sharef.WithHistory(1).History(); history[0] != 1 {
+		t.Errorf("Expected '1', but got: '%d'.", history[0])
+	}
+}
+
+// ctxOnlyLocker is a gobox.CtxLocker which doesn't implement
+// sync.Locker.
+type ctxOnlyLocker struct {
+	mutex gobox.CtxMutex
+	locks *int
+}
+
+func (this ctxOnlyLocker) LockCtx(ctx context.Context) error {
+	if err := this.mutex.LockCtx(ctx); err != nil {
+		return err
+	}
+	*this.locks++
+	return nil
+}
+
+func (this ctxOnlyLocker) Unlock() {
+	this.mutex.Unlock()
+}
+
+func Test_Sharef_NewAtomicCtx(t *testing.T) {
+	locker := ctxOnlyLocker{mutex: gobox.NewCtxMutex(), locks: new(int)}
+	sharef := NewAtomicCtx(0, locker).WithHistory(2)
+
+	sharef.Swap(func(pointer *int) *int {
+		value := *pointer + 1
+		return &value
+	})
+	if _, err := json.Marshal(sharef); err != nil {
+		t.Fatal(err)
+	}
+
+	if *locker.locks != 3 {
+		t.Errorf("Every acquisition should have gone through LockCtx(), but got: '%d'.", *locker.locks)
+	}
+
+	locker.mutex.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := sharef.SwapCtx(ctx, func(pointer *int) *int { return pointer }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, but got: '%v'.", err)
+	}
+}
+
+func Test_Sharef_NewAtomicCtx_Nil_Locker_Panics(t *testing.T) {
+	AssertPanic(func() {
+		NewAtomicCtx[int](0, nil)
+	}, "Nil locker should have caused a panic.", t)
+}
+
+func Test_Sharef_SwapCtx_Errors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := NewAtomic(0).SwapCtx(ctx, func(pointer *int) *int { return pointer }); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but got: '%v'.", err)
+	}
+
+	var zero Sharef[int]
+	if err := zero.SwapCtx(context.Background(), func(pointer *int) *int { return pointer }); !errors.Is(err, ErrNilState) {
+		t.Errorf("Expected ErrNilState, but got: '%v'.", err)
+	}
+}
+
+func Test_Sharef_TryDo_TrySwap_Skip_Contended(t *testing.T) {
+	for _, locker := range []sync.Locker{&sync.Mutex{}, &Spinlock{}, gobox.NewCtxMutex()} {
+		sharef := NewAtomicWith(0, locker)
+
+		locker.Lock()
+		executed := sharef.TryDo(func(portal Portal[int]) {
+			t.Errorf("TryDo() should not execute its body while '%T' is held.", locker)
+		})
+		if executed {
+			t.Errorf("TryDo() should have reported its body as skipped, with locker '%T'.", locker)
+		}
+		if sharef.TrySwap(func(pointer *int) *int { return pointer }) {
+			t.Errorf("TrySwap() should have reported its body as skipped, with locker '%T'.", locker)
+		}
+		locker.Unlock()
+
+		executed = sharef.TryDo(func(portal Portal[int]) {
+			value := *<-portal.Reader + 1
+			portal.Writer <- &value
+		})
+		if !executed || !sharef.TrySwap(func(pointer *int) *int { return nil }) {
+			t.Errorf("Uncontended bodies should have been executed, with locker '%T'.", locker)
+		}
+		if sharef.TrySwap(func(pointer *int) *int { return pointer }) {
+			t.Errorf("TrySwap() should not execute its body on a dead Sharef, with locker '%T'.", locker)
+		}
+	}
+}
+
+func Test_Sharef_TryDo_ZeroValue_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var sharef Sharef[int]
+		sharef.TryDo(func(portal Portal[int]) {})
+	}, "Zero value should have caused a panic.", t)
+}
+
+func Test_Spinlock_TryLock(t *testing.T) {
+	spinlock := &Spinlock{}
+
+	if !spinlock.TryLock() {
+		t.Fatal("TryLock should have acquired an unlocked Spinlock.")
+	}
+	if spinlock.TryLock() {
+		t.Fatal("TryLock should have failed on a locked Spinlock.")
+	}
+
+	spinlock.Unlock()
+	AssertPanic(spinlock.Unlock, "Unlocking an unlocked Spinlock should have caused a panic.", t)
+}
+
 func Test_Sharef_Do_Nesting(t *testing.T) {
 	sharef := New(0)
 
@@ -188,7 +631,7 @@ func Test_Sharef_Do_Nesting(t *testing.T) {
 
 func Test_Sharef_Do_Reader_And_Writer_Are_Automatically_Closed(t *testing.T) {
 	sharef := New(0)
-	panicked := false
+	var leaked Portal[int]
 
 	sharef.Do(func(portal Portal[int]) {
 		pointer := <-portal.Reader
@@ -200,39 +643,64 @@ func Test_Sharef_Do_Reader_And_Writer_Are_Automatically_Closed(t *testing.T) {
 		}
 
 		portal.Writer <- pointer
+		leaked = portal
+	})
 
-		defer func() {
-			if r := recover(); r != nil {
-				panicked = true
-			}
-		}()
+	AssertPanic(func() {
+		leaked.Writer <- new(int)
+	}, "Writing after Do() returned should have caused a panic.", t)
+}
 
-		// This should panic.
-		portal.Writer <- pointer
-	})
+func Test_Sharef_Do_Second_Write_Panics(t *testing.T) {
+	sharef := New(0)
+	one, two := 1, 2
+
+	AssertPanic(func() {
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &one
+			portal.Writer <- &two
+		})
+	}, "Second write should have caused a panic.", t)
 
 	sharef.Do(func(portal Portal[int]) {
 		pointer := <-portal.Reader
-		if pointer == nil {
-			t.Error("Pointer should not be nil.")
+		if *pointer != 0 {
+			t.Errorf("Nothing should have been committed, but got: '%d'.", *pointer)
 		}
 		portal.Writer <- pointer
 	})
-
-	if !panicked {
-		t.Error("Second write should have caused a panic.")
-	}
 }
 
-func Test_Sharef_Do_Last_Write_Wins(t *testing.T) {
+func Test_Sharef_Do_Portal_Handed_To_Goroutine(t *testing.T) {
 	sharef := New(0)
-	ten := 10
 
-	sharef.Do(func(portalA Portal[int]) {
-		sharef.Do(func(portalB Portal[int]) {
-			pointerB := <-portalB.Reader
-			*pointerB++
-			portalB.Writer <- pointerB
+	sharef.Do(func(portal Portal[int]) {
+		go func() {
+			pointer := <-portal.Reader
+			*pointer++
+			portal.Writer <- pointer
+		}()
+	})
+
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 1 {
+			t.Errorf("Expected '1', but got: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+}
+
+func Test_Sharef_Do_Last_Write_Wins(t *testing.T) {
+	sharef := New(0)
+	ten := 10
+
+	sharef.Do(func(portalA Portal[int]) {
+		sharef.Do(func(portalB Portal[int]) {
+			pointerB := <-portalB.Reader
+			*pointerB++
+			portalB.Writer <- pointerB
 		})
 
 		pointerA := <-portalA.Reader
@@ -358,6 +826,97 @@ func Test_Sharef_Mutation(t *testing.T) {
 	})
 }
 
+func Test_Sharef_OnReadWrite(t *testing.T) {
+	sharef := New(0)
+	seqPrevious := make([]int, 0)
+	seqCurrent := make([]int, 0)
+
+	unsubscribe := sharef.OnReadWrite(func(previous *int, current *int) {
+		seqPrevious = append(seqPrevious, *previous)
+		seqCurrent = append(seqCurrent, *current)
+	})
+
+	increment := func(copy Sharef[int]) {
+		copy.Do(func(portal Portal[int]) {
+			pointer := <-portal.Reader
+			value := *pointer + 1
+			portal.Writer <- &value
+		})
+	}
+
+	increment(sharef)
+	increment(sharef)
+	unsubscribe()
+	increment(sharef)
+
+	if len(seqPrevious) != 2 || seqPrevious[0] != 0 || seqPrevious[1] != 1 {
+		t.Errorf("Unexpected previous values: '%v'.", seqPrevious)
+	}
+
+	if len(seqCurrent) != 2 || seqCurrent[0] != 1 || seqCurrent[1] != 2 {
+		t.Errorf("Unexpected current values: '%v'.", seqCurrent)
+	}
+}
+
+func Test_Sharef_OnReadWrite_ZeroValue_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var sharef Sharef[int]
+		sharef.OnReadWrite(func(previous *int, current *int) {})
+	}, "Zero value should have caused a panic.", t)
+}
+
+func Test_Sharef_History(t *testing.T) {
+	sharef := New(0).WithHistory(3)
+
+	for value := 1; value <= 4; value++ {
+		written := value
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &written
+		})
+
+		// Reading should not record repeated values.
+		sharef.Do(func(portal Portal[int]) {
+			portal.Writer <- <-portal.Reader
+		})
+	}
+
+	history := sharef.History()
+	if len(history) != 3 || history[0] != 2 || history[1] != 3 || history[2] != 4 {
+		t.Errorf("Unexpected history: '%v'.", history)
+	}
+}
+
+func Test_Sharef_History_Collapses_Consecutive_Duplicates(t *testing.T) {
+	sharef := New(1).WithHistory(5)
+
+	for _, value := range []int{2, 2, 1} {
+		written := value
+		sharef.Swap(func(*int) *int {
+			return &written
+		})
+	}
+
+	history := sharef.History()
+	if len(history) != 3 || history[0] != 1 || history[1] != 2 || history[2] != 1 {
+		t.Errorf("Unexpected history: '%v'.", history)
+	}
+}
+
+func Test_Sharef_History_Disabled(t *testing.T) {
+	sharef := New(0)
+
+	sharef.Do(func(portal Portal[int]) {
+		<-portal.Reader
+		value := 1
+		portal.Writer <- &value
+	})
+
+	if len(sharef.History()) != 0 {
+		t.Errorf("History should be empty, but was: '%v'.", sharef.History())
+	}
+}
+
 func Test_Group_New_Pointer_Panics(t *testing.T) {
 	AssertPanic(func() {
 		x := 10
@@ -367,6 +926,23 @@ func Test_Group_New_Pointer_Panics(t *testing.T) {
 	}, "Pointer should have caused a panic.", t)
 }
 
+func Test_Group_ZeroValue_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var group Group[int]
+		group.New("foo", 10)
+	}, "Zero value should have caused a panic on New().", t)
+
+	AssertPanic(func() {
+		var group Group[int]
+		group.OnReadWrite(func(ReadWriteEvent[int]) {})
+	}, "Zero value should have caused a panic on OnReadWrite().", t)
+
+	AssertPanic(func() {
+		var group Group[int]
+		group.Names()
+	}, "Zero value should have caused a panic on Names().", t)
+}
+
 func Test_Group_OnReadWrite(t *testing.T) {
 	cycles := 100
 
@@ -435,3 +1011,960 @@ func Test_Group_OnReadWrite(t *testing.T) {
 		t.Error("Incorrect sharef name.")
 	}
 }
+
+func Test_Group_OnReadWrite_Multiple_Subscribers(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	sharef := group.New("sharef-1", 0)
+
+	order := make([]string, 0)
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		order = append(order, "first")
+	})
+	unsubscribe := group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		order = append(order, "second")
+	})
+
+	write := func() {
+		sharef.Do(func(portal Portal[int]) {
+			portal.Writer <- <-portal.Reader
+		})
+	}
+
+	write()
+	unsubscribe()
+	unsubscribe()
+	write()
+
+	expected := []string{"first", "second", "first"}
+	if len(order) != len(expected) {
+		t.Fatalf("Unexpected delivery: '%v'.", order)
+	}
+	for index := range expected {
+		if order[index] != expected[index] {
+			t.Fatalf("Unexpected delivery: '%v'.", order)
+		}
+	}
+}
+
+func Test_Group_OnReadWrite_Sequence_And_Time(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	events := make(map[string][]ReadWriteEvent[int])
+
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		events[event.SharefName] = append(events[event.SharefName], event)
+	})
+
+	before := time.Now()
+	first := group.New("sharef-1", 0)
+	second := group.New("sharef-2", 0)
+	copied := first
+
+	first.Swap(func(current *int) *int { return current })
+	second.Swap(func(current *int) *int { return current })
+	copied.Swap(func(current *int) *int { return current })
+	first.Do(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+
+	for name, count := range map[string]int{"sharef-1": 3, "sharef-2": 1} {
+		if len(events[name]) != count {
+			t.Fatalf("Expected %d events for '%s', but got: '%d'.", count, name, len(events[name]))
+		}
+
+		for index, event := range events[name] {
+			if event.Sequence != uint64(index+1) {
+				t.Errorf("Expected sequence '%d' for '%s', but got: '%d'.", index+1, name, event.Sequence)
+			}
+			if event.Time.Before(before) || event.Time.After(time.Now()) {
+				t.Errorf("Unexpected time for '%s': '%s'.", name, event.Time)
+			}
+			if index > 0 && event.Time.Before(events[name][index-1].Time) {
+				t.Errorf("Time should not go backwards for '%s'.", name)
+			}
+		}
+	}
+}
+
+func Test_Group_OnReadWriteWhere(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	matched := make([]ReadWriteEvent[int], 0)
+
+	stop := group.OnReadWriteWhere(func(event ReadWriteEvent[int]) bool {
+		return event.SharefName == "sharef-1" && event.Current == nil
+	}, func(event ReadWriteEvent[int]) {
+		matched = append(matched, event)
+	})
+
+	first := group.New("sharef-1", 1)
+	second := group.New("sharef-2", 2)
+
+	first.Swap(func(current *int) *int { return current })
+	second.Swap(func(*int) *int { return nil })
+	first.Swap(func(*int) *int { return nil })
+
+	if len(matched) != 1 {
+		t.Fatalf("Expected 1 matching event, but got: '%d'.", len(matched))
+	}
+	if matched[0].SharefName != "sharef-1" || *matched[0].Previous != 1 {
+		t.Errorf("Unexpected event: '%+v'.", matched[0])
+	}
+
+	stop()
+	group.New("sharef-1", 3).Swap(func(*int) *int { return nil })
+	if len(matched) != 1 {
+		t.Error("Callback should no longer be invoked once unregistered.")
+	}
+}
+
+func Test_Group_Get_Names_Len(t *testing.T) {
+	group := NewGroup[int]("group-1")
+
+	if group.Len() != 0 {
+		t.Errorf("Empty group should have length 0, but it had: '%d'.", group.Len())
+	}
+
+	group.New("b", 2)
+	group.New("a", 1)
+
+	if group.Len() != 2 {
+		t.Errorf("Group should have length 2, but it had: '%d'.", group.Len())
+	}
+
+	names := group.Names()
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Unexpected names: '%v'.", names)
+	}
+
+	sharef, found := group.Get("a")
+	if !found {
+		t.Fatal("Sharef 'a' should have been found.")
+	}
+
+	// The registered Sharef must be a copy of the one returned by
+	// New(), referring to the same value.
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 1 {
+			t.Errorf("Value should be 1, but instead it was: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+
+	if _, found := group.Get("c"); found {
+		t.Error("Sharef 'c' should not have been found.")
+	}
+}
+
+func Test_Group_New_Duplicate_Name_Latest_Wins(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	previous := group.New("foo", 1)
+	latest := group.New("foo", 2)
+
+	found, _ := group.Get("foo")
+	if found.core != latest.core || group.Len() != 1 {
+		t.Error("The latest Sharef should have replaced the previous one.")
+	}
+
+	names := make([]string, 0)
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		names = append(names, event.SharefName)
+	})
+	previous.Swap(func(pointer *int) *int { return pointer })
+
+	if len(names) != 1 || names[0] != "foo" {
+		t.Errorf("The previous Sharef should still report to the Group, but got: '%v'.", names)
+	}
+}
+
+func Test_Sharef_JSON(t *testing.T) {
+	type State struct {
+		Counter Sharef[Counter]
+		Missing Sharef[int]
+	}
+
+	state := State{Counter: New(Counter{Value: 1}), Missing: Dead[int]()}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Counter":{"Value":1},"Missing":null}` {
+		t.Errorf("Unexpected encoding: '%s'.", data)
+	}
+
+	// Re-seeding an existing Sharef must be observed by its copies.
+	copy := state.Counter
+	if err := json.Unmarshal([]byte(`{"Counter":{"Value":2},"Missing":3}`), &state); err != nil {
+		t.Fatal(err)
+	}
+
+	copy.Do(func(portal Portal[Counter]) {
+		pointer := <-portal.Reader
+		if pointer.Value != 2 {
+			t.Errorf("Value should be 2, but instead it was: '%d'.", pointer.Value)
+		}
+		portal.Writer <- pointer
+	})
+
+	if state.Missing.IsDead() {
+		t.Error("Unmarshaling a value should have revived the Sharef.")
+	}
+
+	var decoded State
+	if err := json.Unmarshal([]byte(`{"Counter":{"Value":4},"Missing":null}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Counter.IsDead() || decoded.Missing.IsAlive() {
+		t.Error("Zero value Sharefs should have been initialized.")
+	}
+}
+
+func Test_Sharef_UnmarshalJSON_Validates_And_Records(t *testing.T) {
+	errNegative := errors.New("negative")
+	sharef := NewValidated(1, func(value int) error {
+		if value < 0 {
+			return errNegative
+		}
+		return nil
+	}).WithHistory(3)
+
+	if err := json.Unmarshal([]byte(`-5`), &sharef); !errors.Is(err, errNegative) {
+		t.Errorf("Expected the validator's error, but got: '%v'.", err)
+	}
+	if err := json.Unmarshal([]byte(`2`), &sharef); err != nil {
+		t.Fatal(err)
+	}
+
+	if history := sharef.History(); len(history) != 2 || history[0] != 1 || history[1] != 2 {
+		t.Errorf("Unexpected history: '%v'.", history)
+	}
+}
+
+func Test_Group_MarshalJSON(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	group.New("b", 2)
+	group.New("a", 1)
+
+	data, err := json.Marshal(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"a":1,"b":2}` {
+		t.Errorf("Unexpected encoding: '%s'.", data)
+	}
+}
+
+func Test_Group_Async_Delivers_In_Order(t *testing.T) {
+	cycles := 100
+
+	group := NewGroup[int]("group-1")
+	stop := group.Async(cycles, Block)
+	defer stop()
+
+	sequence := make([]int, 0)
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		sequence = append(sequence, *event.Current)
+	})
+
+	sharef := group.New("sharef-1", 0)
+	for value := 1; value <= cycles; value++ {
+		written := value
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &written
+		})
+	}
+
+	group.Flush()
+
+	if len(sequence) != cycles {
+		t.Fatalf("Expected '%d' events, but got '%d'.", cycles, len(sequence))
+	}
+	for index, value := range sequence {
+		if value != index+1 {
+			t.Fatalf("Events were delivered out of order: '%v'.", sequence)
+		}
+	}
+}
+
+func Test_Group_Async_Overflow(t *testing.T) {
+	check := func(policy OverflowPolicy, expected []int) {
+		group := NewGroup[int]("group-1")
+		stop := group.Async(2, policy)
+		defer stop()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		sequence := make([]int, 0)
+		group.OnReadWrite(func(event ReadWriteEvent[int]) {
+			if *event.Current == 1 {
+				close(started)
+				<-release
+			}
+			sequence = append(sequence, *event.Current)
+		})
+
+		sharef := group.New("sharef-1", 0)
+		write := func(value int) {
+			sharef.Do(func(portal Portal[int]) {
+				<-portal.Reader
+				portal.Writer <- &value
+			})
+		}
+
+		// The first event occupies the subscriber, the remaining ones
+		// overflow the queue.
+		write(1)
+		<-started
+		for value := 2; value <= 5; value++ {
+			write(value)
+		}
+		close(release)
+		group.Flush()
+
+		if len(sequence) != len(expected) {
+			t.Fatalf("Policy '%d': unexpected events: '%v'.", policy, sequence)
+		}
+		for index := range expected {
+			if sequence[index] != expected[index] {
+				t.Fatalf("Policy '%d': unexpected events: '%v'.", policy, sequence)
+			}
+		}
+	}
+
+	check(DropNewest, []int{1, 2, 3})
+	check(DropOldest, []int{1, 4, 5})
+}
+
+func Test_Group_Async_Stop(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	stop := group.Async(10, Block)
+
+	count := 0
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		count++
+	})
+
+	sharef := group.New("sharef-1", 0)
+	sharef.Do(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+
+	// Stopping delivers the queued events.
+	stop()
+	if count != 1 {
+		t.Fatalf("Expected 1 event, but got '%d'.", count)
+	}
+
+	// Delivery is synchronous again.
+	sharef.Do(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+	if count != 2 {
+		t.Fatalf("Expected 2 events, but got '%d'.", count)
+	}
+}
+
+func Test_Group_Async_Conflate(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	stop := group.Async(10, Conflate)
+	defer stop()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	events := make([]ReadWriteEvent[int], 0)
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		if event.SharefName == "a" && *event.Current == 1 {
+			close(started)
+			<-release
+		}
+		events = append(events, event)
+	})
+
+	a := group.New("a", 0)
+	b := group.New("b", 0)
+	write := func(sharef Sharef[int], value int) {
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &value
+		})
+	}
+
+	// The first event occupies the subscriber, the following writes
+	// to each Sharef are collapsed.
+	write(a, 1)
+	<-started
+	for value := 2; value <= 5; value++ {
+		write(a, value)
+		write(b, value*10)
+	}
+	close(release)
+	group.Flush()
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, but got '%d'.", len(events))
+	}
+
+	check := func(event ReadWriteEvent[int], name string, previous int, current int) {
+		if event.SharefName != name || *event.Previous != previous || *event.Current != current {
+			t.Errorf("Unexpected event: '%s', '%d', '%d'.", event.SharefName, *event.Previous, *event.Current)
+		}
+	}
+	check(events[0], "a", 0, 1)
+	check(events[1], "a", 1, 5)
+	check(events[2], "b", 0, 50)
+}
+
+func Test_Group_WAL(t *testing.T) {
+	log := bytes.Buffer{}
+
+	group := NewGroup[int]("group-1")
+	stop := group.WAL(&log, JSONCodec[int]{})
+
+	a := group.New("a", 0)
+	b := group.New("b", 0)
+	write := func(sharef Sharef[int], pointer *int) {
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- pointer
+		})
+	}
+	one, two, three := 1, 2, 3
+	write(a, &one)
+	write(b, &two)
+	write(a, &three)
+	write(b, nil)
+
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	write(a, &one)
+
+	expected := `{"name":"a","sequence":1,"value":"MQ=="}
+{"name":"b","sequence":2,"value":"Mg=="}
+{"name":"a","sequence":3,"value":"Mw=="}
+{"name":"b","sequence":4,"value":null}
+`
+	if log.String() != expected {
+		t.Fatalf("Unexpected log: '%s'.", log.String())
+	}
+
+	restored := NewGroup[int]("group-1")
+	c := restored.New("b", 10)
+	if err := restored.ReplayWAL(&log, JSONCodec[int]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Len() != 2 {
+		t.Errorf("Restored group should have length 2, but it had: '%d'.", restored.Len())
+	}
+
+	restoredA, _ := restored.Get("a")
+	restoredA.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 3 {
+			t.Errorf("Value should be 3, but instead it was: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+
+	if c.IsAlive() {
+		t.Error("Existing Sharef should have been killed by the replay.")
+	}
+}
+
+func Test_Group_WAL_Gob(t *testing.T) {
+	log := bytes.Buffer{}
+
+	group := NewGroup[Counter]("group-1")
+	stop := group.WAL(&log, GobCodec[Counter]{})
+
+	sharef := group.New("counter", Counter{Value: 0})
+	IncByValue(sharef)
+	IncByValue(sharef)
+
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewGroup[Counter]("group-1")
+	if err := restored.ReplayWAL(&log, GobCodec[Counter]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	counter, found := restored.Get("counter")
+	if !found {
+		t.Fatal("Sharef 'counter' should have been restored.")
+	}
+	counter.Do(func(portal Portal[Counter]) {
+		pointer := <-portal.Reader
+		if pointer.Value != 2 {
+			t.Errorf("Value should be 2, but instead it was: '%d'.", pointer.Value)
+		}
+		portal.Writer <- pointer
+	})
+}
+
+func Test_Codecs(t *testing.T) {
+	codecs := []Codec[Counter]{JSONCodec[Counter]{}, GobCodec[Counter]{}}
+
+	for _, codec := range codecs {
+		data, err := codec.Encode(Counter{Value: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counter, err := codec.Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if counter.Value != 7 {
+			t.Errorf("%T: value should be 7, but instead it was: '%d'.", codec, counter.Value)
+		}
+	}
+}
+
+func Test_VersionedCodec_Migrations(t *testing.T) {
+	type V1 struct {
+		Count int
+	}
+	type V3 struct {
+		Total int
+		Unit  string
+	}
+
+	old := NewVersionedCodec[V1](JSONCodec[V1]{}, 1)
+	data, err := old.Encode(V1{Count: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := NewVersionedCodec[V3](JSONCodec[V3]{}, 3)
+	if _, err := current.Decode(data); !errors.Is(err, ErrNoMigration) {
+		t.Errorf("Expected ErrNoMigration, but got: '%v'.", err)
+	}
+
+	current.RegisterMigration(1, 2, func(data []byte) ([]byte, error) {
+		return bytes.Replace(data, []byte(`"Count"`), []byte(`"Total"`), 1), nil
+	})
+	current.RegisterMigration(2, 3, func(data []byte) ([]byte, error) {
+		return bytes.Replace(data, []byte(`}`), []byte(`,"Unit":"items"}`), 1), nil
+	})
+
+	value, err := current.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Total != 5 || value.Unit != "items" {
+		t.Errorf("Unexpected migrated value: '%v'.", value)
+	}
+
+	if _, err := current.Decode(nil); !errors.Is(err, ErrMalformedData) {
+		t.Errorf("Expected ErrMalformedData, but got: '%v'.", err)
+	}
+}
+
+func Test_VersionedCodec_Cyclic_Migrations(t *testing.T) {
+	codec := NewVersionedCodec[int](JSONCodec[int]{}, 3)
+	codec.RegisterMigration(1, 2, func(data []byte) ([]byte, error) {
+		return data, nil
+	})
+	codec.RegisterMigration(2, 1, func(data []byte) ([]byte, error) {
+		return data, nil
+	})
+
+	old := NewVersionedCodec[int](JSONCodec[int]{}, 1)
+	data, _ := old.Encode(1)
+
+	if _, err := codec.Decode(data); !errors.Is(err, ErrNoMigration) {
+		t.Errorf("Expected ErrNoMigration, but got: '%v'.", err)
+	}
+}
+
+func Test_Group_WAL_Versioned(t *testing.T) {
+	log := bytes.Buffer{}
+	codec := NewVersionedCodec[int](JSONCodec[int]{}, 1)
+
+	group := NewGroup[int]("group-1")
+	stop := group.WAL(&log, codec)
+	sharef := group.New("a", 0)
+	sharef.Do(func(portal Portal[int]) {
+		<-portal.Reader
+		value := 21
+		portal.Writer <- &value
+	})
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The value's representation changed in version 2, doubling it.
+	upgraded := NewVersionedCodec[int](JSONCodec[int]{}, 2)
+	upgraded.RegisterMigration(1, 2, func(data []byte) ([]byte, error) {
+		value := 0
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return json.Marshal(value * 2)
+	})
+
+	restored := NewGroup[int]("group-1")
+	if err := restored.ReplayWAL(&log, upgraded); err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := restored.Get("a")
+	a.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		if *pointer != 42 {
+			t.Errorf("Value should be 42, but instead it was: '%d'.", *pointer)
+		}
+		portal.Writer <- pointer
+	})
+}
+
+func Test_Group_Record_And_Replay(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	a := group.New("a", 0)
+
+	recording, stop := group.Record()
+
+	write := func(sharef Sharef[int], value int) {
+		sharef.Do(func(portal Portal[int]) {
+			<-portal.Reader
+			portal.Writer <- &value
+		})
+	}
+	write(a, 1)
+	b := group.New("b", 10)
+	write(b, 20)
+	write(a, 2)
+	stop()
+	write(a, 3)
+
+	if len(recording.Steps()) != 3 {
+		t.Fatalf("Expected 3 recorded steps, but got '%d'.", len(recording.Steps()))
+	}
+
+	fresh := NewGroup[int]("group-1")
+	replayer := NewReplayer(recording, &fresh)
+
+	read := func(name string) int {
+		sharef, found := fresh.Get(name)
+		if !found {
+			t.Fatalf("Sharef '%s' should exist.", name)
+		}
+		value := 0
+		sharef.Do(func(portal Portal[int]) {
+			pointer := <-portal.Reader
+			value = *pointer
+			portal.Writer <- pointer
+		})
+		return value
+	}
+	check := func(expectedA int, expectedB int) {
+		if read("a") != expectedA || read("b") != expectedB {
+			t.Errorf("Position '%d': expected '%d', '%d', but got '%d', '%d'.",
+				replayer.Position(), expectedA, expectedB, read("a"), read("b"))
+		}
+	}
+
+	check(0, 10)
+	replayer.Step()
+	check(1, 10)
+	replayer.Step()
+	check(1, 20)
+	replayer.Step()
+	check(2, 20)
+
+	if replayer.Step() {
+		t.Error("Stepping past the end should have no effect.")
+	}
+
+	if err := replayer.Seek(1); err != nil {
+		t.Fatal(err)
+	}
+	check(1, 10)
+
+	if err := replayer.Seek(4); !errors.Is(err, ErrPosition) {
+		t.Errorf("Expected ErrPosition, but got: '%v'.", err)
+	}
+
+	if err := replayer.Play(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	check(2, 20)
+}
+
+func Test_Replayer_Play_Pause(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	recording, stop := group.Record()
+	sharef := group.New("a", 0)
+	increment := func() {
+		sharef.Do(func(portal Portal[int]) {
+			pointer := <-portal.Reader
+			value := *pointer + 1
+			portal.Writer <- &value
+		})
+	}
+	increment()
+	increment()
+	stop()
+
+	fresh := NewGroup[int]("group-1")
+	replayer := NewReplayer(recording, &fresh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := replayer.Play(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but got: '%v'.", err)
+	}
+
+	if replayer.Position() != 0 {
+		t.Errorf("Replay should be paused at position 0, but was at '%d'.", replayer.Position())
+	}
+}
+
+func writeFromHelper(sharef Sharef[int]) {
+	sharef.Do(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+}
+
+func Test_Group_CaptureCallers(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	sharef := group.New("sharef-1", 0)
+
+	var callers []runtime.Frame
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		callers = event.Callers
+	})
+
+	writeFromHelper(sharef)
+	if callers != nil {
+		t.Fatal("Callers should not be captured by default.")
+	}
+
+	group.CaptureCallers(2)
+	writeFromHelper(sharef)
+
+	if len(callers) != 2 {
+		t.Fatalf("Expected 2 frames, but got: '%v'.", callers)
+	}
+	if !strings.HasSuffix(callers[0].Function, ".writeFromHelper") {
+		t.Errorf("First frame should be the caller of Do(), but was: '%s'.", callers[0].Function)
+	}
+	if !strings.HasSuffix(callers[1].Function, ".Test_Group_CaptureCallers") {
+		t.Errorf("Second frame should be the test, but was: '%s'.", callers[1].Function)
+	}
+
+	sharef.DoE(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+	if !strings.HasSuffix(callers[0].Function, ".Test_Group_CaptureCallers") {
+		t.Errorf("First frame should be the caller of DoE(), but was: '%s'.", callers[0].Function)
+	}
+
+	sharef.Swap(func(pointer *int) *int {
+		return pointer
+	})
+	if !strings.HasSuffix(callers[0].Function, ".Test_Group_CaptureCallers") {
+		t.Errorf("First frame should be the caller of Swap(), but was: '%s'.", callers[0].Function)
+	}
+
+	group.CaptureCallers(0)
+	writeFromHelper(sharef)
+	if callers != nil {
+		t.Error("Callers should no longer be captured.")
+	}
+}
+
+func Test_Group_OnDeath(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	deaths := make([]DeathEvent[int], 0)
+
+	stop := group.OnDeath(func(event DeathEvent[int]) {
+		deaths = append(deaths, event)
+	})
+
+	sharef := group.New("sharef-1", 10)
+	sharef.Swap(func(current *int) *int {
+		value := *current + 1
+		return &value
+	})
+	if len(deaths) != 0 {
+		t.Fatal("Writes which don't kill the Sharef should not be reported.")
+	}
+
+	sharef.Do(func(portal Portal[int]) {
+		<-portal.Reader
+		portal.Writer <- nil
+	})
+
+	if len(deaths) != 1 {
+		t.Fatalf("Expected 1 death, but got: '%d'.", len(deaths))
+	}
+	death := deaths[0]
+	if death.GroupName != "group-1" || death.SharefName != "sharef-1" {
+		t.Errorf("Unexpected names: '%s', '%s'.", death.GroupName, death.SharefName)
+	}
+	if death.Last != 11 {
+		t.Errorf("Expected the last live value '11', but got: '%d'.", death.Last)
+	}
+	if death.Sequence != 2 {
+		t.Errorf("Expected sequence '2', but got: '%d'.", death.Sequence)
+	}
+	if len(death.Callers) == 0 || !strings.HasSuffix(death.Callers[0].Function, ".Test_Group_OnDeath") {
+		t.Errorf("First frame should be the caller of Do(), but was: '%v'.", death.Callers)
+	}
+
+	// Writes to a dead Sharef have no effect, so it dies only once.
+	sharef.Swap(func(*int) *int { return nil })
+
+	stop()
+	stop()
+	group.New("sharef-2", 0).Swap(func(*int) *int { return nil })
+	if len(deaths) != 1 {
+		t.Errorf("Expected 1 death, but got: '%d'.", len(deaths))
+	}
+
+	var callers []runtime.Frame
+	group.OnReadWrite(func(event ReadWriteEvent[int]) {
+		callers = event.Callers
+	})
+	group.New("sharef-3", 0).Swap(func(*int) *int { return nil })
+	if callers != nil {
+		t.Error("Callers should not be captured once every OnDeath callback is unregistered.")
+	}
+}
+
+func Test_Group_RetainEvents(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	sharef := group.New("sharef-1", 0)
+	increment := func() {
+		sharef.Do(func(portal Portal[int]) {
+			value := *<-portal.Reader + 1
+			portal.Writer <- &value
+		})
+	}
+
+	if len(group.Events()) != 0 {
+		t.Fatal("Events should not be retained by default.")
+	}
+
+	group.RetainEvents(5)
+	stop := group.RetainEvents(2)
+	for count := 0; count < 3; count++ {
+		increment()
+	}
+
+	events := group.Events()
+	if len(events) != 2 || events[0].Sequence != 2 || events[1].Sequence != 3 {
+		t.Fatalf("Expected the 2 latest events, but got: '%+v'.", events)
+	}
+	if *events[0].Previous != 1 || *events[0].Current != 2 {
+		t.Errorf("Unexpected retained values: '%d', '%d'.", *events[0].Previous, *events[0].Current)
+	}
+
+	if stats := group.Stats(); stats["sharef-1"].Writes != 3 || !stats["sharef-1"].Alive {
+		t.Errorf("Unexpected stats: '%+v'.", stats)
+	}
+
+	stop()
+	increment()
+	if len(group.Events()) != 0 {
+		t.Error("Events should be discarded once retaining stops.")
+	}
+}
+
+func Test_Sharef_WithWatchdog(t *testing.T) {
+	group := NewGroup[int]("group-1")
+	reports := make(chan Abandoned, 2)
+	sharef := group.New("sharef-1", 0).WithWatchdog(10*time.Millisecond, func(abandoned Abandoned) {
+		reports <- abandoned
+	})
+
+	// A prompt write is not reported.
+	sharef.Do(func(portal Portal[int]) {
+		portal.Writer <- <-portal.Reader
+	})
+
+	sharef.Do(func(portal Portal[int]) {
+		pointer := <-portal.Reader
+		time.Sleep(100 * time.Millisecond)
+		portal.Writer <- pointer
+	})
+
+	select {
+	case abandoned := <-reports:
+		if abandoned.SharefName != "sharef-1" {
+			t.Errorf("Unexpected Sharef name: '%s'.", abandoned.SharefName)
+		}
+		if abandoned.Timeout != 10*time.Millisecond {
+			t.Errorf("Unexpected timeout: '%s'.", abandoned.Timeout)
+		}
+		if !strings.Contains(abandoned.Stack, "Test_Sharef_WithWatchdog") {
+			t.Errorf("Stack should point at the abandoned function: '%s'.", abandoned.Stack)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Abandoned Portal should have been reported.")
+	}
+
+	if len(reports) != 0 {
+		t.Error("Each abandoned Portal should be reported once.")
+	}
+}
+
+func Test_Sharef_WithWatchdog_ZeroValue_Panics(t *testing.T) {
+	AssertPanic(func() {
+		var sharef Sharef[int]
+		sharef.WithWatchdog(time.Second, func(Abandoned) {})
+	}, "Zero value should have caused a panic.", t)
+}
+
+func Benchmark_Sharef_Do(b *testing.B) {
+	sharef := New(0)
+	b.ReportAllocs()
+
+	for index := 0; index < b.N; index++ {
+		sharef.Do(func(portal Portal[int]) {
+			pointer := <-portal.Reader
+			*pointer++
+			portal.Writer <- pointer
+		})
+	}
+}
+
+func Benchmark_Sharef_Swap(b *testing.B) {
+	sharef := New(0)
+	b.ReportAllocs()
+
+	for index := 0; index < b.N; index++ {
+		sharef.Swap(func(pointer *int) *int {
+			*pointer++
+			return pointer
+		})
+	}
+}
+
+// constructed keeps the constructor benchmarks' results alive, so
+// the compiler can't optimize their allocations away.
+var constructed Sharef[int]
+
+func Benchmark_Sharef_New(b *testing.B) {
+	b.ReportAllocs()
+
+	for index := 0; index < b.N; index++ {
+		constructed = New(index)
+	}
+}
+
+func Benchmark_Sharef_NewScalar(b *testing.B) {
+	b.ReportAllocs()
+
+	for index := 0; index < b.N; index++ {
+		constructed = NewScalar(index)
+	}
+}
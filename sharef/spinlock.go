@@ -0,0 +1,47 @@
+package sharef
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Spinlock is a sync.Locker which busy-waits instead of parking the
+// goroutine, yielding the processor through runtime.Gosched() between
+// attempts; It suits extremely short critical sections, where parking
+// costs more than waiting, and should be avoided otherwise;
+// The zero value is an unlocked Spinlock.
+type Spinlock struct {
+	locked atomic.Bool
+}
+
+// Lock acquires the Spinlock, spinning until it becomes available;
+// Contended attempts back off exponentially, yielding the processor
+// more times between attempts, up to a bound.
+func (this *Spinlock) Lock() {
+	backoff := 1
+
+	for !this.TryLock() {
+		for yield := 0; yield < backoff; yield++ {
+			runtime.Gosched()
+		}
+
+		if backoff < 16 {
+			backoff <<= 1
+		}
+	}
+}
+
+// TryLock acquires the Spinlock if it is available, without spinning;
+// It returns whether the Spinlock was acquired.
+func (this *Spinlock) TryLock() bool {
+	return !this.locked.Load() && this.locked.CompareAndSwap(false, true)
+}
+
+// Unlock releases the Spinlock;
+// Unlock *panics* if:
+// 1: the Spinlock is not locked.
+func (this *Spinlock) Unlock() {
+	if !this.locked.Swap(false) {
+		panic("Invalid state: Spinlock is not locked.")
+	}
+}
@@ -0,0 +1,65 @@
+package sharef
+
+import "sync"
+
+// subscription associates a callback with the identifier used to
+// remove it later on.
+type subscription[F any] struct {
+	id       int
+	callback F
+}
+
+// subscribers is an ordered collection of callbacks which is safe for
+// concurrent use;
+// Callbacks are always returned in registration order.
+type subscribers[F any] struct {
+	mutex   sync.Mutex
+	next    int
+	entries []subscription[F]
+}
+
+// add registers a callback and returns a function that unregisters
+// it; Calling the returned function more than once has no effect.
+func (this *subscribers[F]) add(callback F) func() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	id := this.next
+	this.next++
+	this.entries = append(this.entries, subscription[F]{
+		id:       id,
+		callback: callback,
+	})
+
+	return func() {
+		this.remove(id)
+	}
+}
+
+// remove unregisters the callback associated with the given
+// identifier, if it is still registered.
+func (this *subscribers[F]) remove(id int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for index, entry := range this.entries {
+		if entry.id == id {
+			this.entries = append(this.entries[:index:index], this.entries[index+1:]...)
+			return
+		}
+	}
+}
+
+// callbacks returns a snapshot of the registered callbacks, so they
+// can be invoked without holding the lock.
+func (this *subscribers[F]) callbacks() []F {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	callbacks := make([]F, len(this.entries))
+	for index, entry := range this.entries {
+		callbacks[index] = entry.callback
+	}
+
+	return callbacks
+}
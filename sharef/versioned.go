@@ -0,0 +1,98 @@
+package sharef
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// migration converts data encoded with one schema version to another.
+type migration struct {
+	to      int
+	migrate func([]byte) ([]byte, error)
+}
+
+// VersionedCodec is a Codec which tags the values it encodes with a
+// schema version, and migrates values encoded with other versions to
+// the current one when decoding them;
+// Wrapping the Codec given to the persistence features, such as
+// Group.WAL() and Group.ReplayWAL(), keeps old data loadable after T
+// evolves.
+type VersionedCodec[T any] struct {
+	codec      Codec[T]
+	version    int
+	migrations map[int]migration
+}
+
+// NewVersionedCodec() creates a new VersionedCodec, encoding values
+// through the given Codec, tagged with the given schema version;
+// NewVersionedCodec *panics* if:
+// 1: the version is negative.
+func NewVersionedCodec[T any](codec Codec[T], version int) *VersionedCodec[T] {
+	if version < 0 {
+		panic("Invalid state: version is negative.")
+	}
+
+	return &VersionedCodec[T]{
+		codec:      codec,
+		version:    version,
+		migrations: make(map[int]migration),
+	}
+}
+
+// RegisterMigration registers a function which converts data encoded
+// with one schema version to another; Migrations are chained when
+// decoding, until the current version is reached;
+// Registering a migration from an already registered version replaces
+// it.
+// *Note*: migrations must be registered before the VersionedCodec is
+// used, as registration is not safe for concurrent use.
+func (this *VersionedCodec[T]) RegisterMigration(from int, to int, migrate func([]byte) ([]byte, error)) {
+	this.migrations[from] = migration{
+		to:      to,
+		migrate: migrate,
+	}
+}
+
+// Encode encodes a value through the wrapped Codec, prefixed with the
+// current schema version.
+func (this *VersionedCodec[T]) Encode(value T) ([]byte, error) {
+	data, err := this.codec.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(binary.AppendUvarint(nil, uint64(this.version)), data...), nil
+}
+
+// Decode migrates data to the current schema version, if needed, and
+// decodes it through the wrapped Codec;
+// Decode returns:
+// 1: ErrMalformedData if the data carries no schema version;
+// 2: ErrNoMigration if no chain of registered migrations leads from
+// the data's schema version to the current one.
+func (this *VersionedCodec[T]) Decode(data []byte) (T, error) {
+	var zero T
+
+	tag, length := binary.Uvarint(data)
+	if length <= 0 {
+		return zero, ErrMalformedData
+	}
+	version, data := int(tag), data[length:]
+
+	// Each migration is applied at most once, so cyclic migrations
+	// can't loop forever.
+	for steps := 0; version != this.version; steps++ {
+		migration, found := this.migrations[version]
+		if !found || steps >= len(this.migrations) {
+			return zero, fmt.Errorf("%w: from version %d to %d", ErrNoMigration, version, this.version)
+		}
+
+		migrated, err := migration.migrate(data)
+		if err != nil {
+			return zero, err
+		}
+		version, data = migration.to, migrated
+	}
+
+	return this.codec.Decode(data)
+}
@@ -0,0 +1,105 @@
+package sharef
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// walRecord is a single committed write within a Group, as stored in
+// its write-ahead log.
+type walRecord struct {
+	Name     string `json:"name"`
+	Sequence uint64 `json:"sequence"`
+	Value    []byte `json:"value"`
+}
+
+// WAL makes the Group append every committed write within it to a
+// write-ahead log, as a JSON record per line holding the Sharef's
+// name, a sequence number, and the written value encoded by the given
+// Codec, in base64, which is null if the write killed the Sharef;
+// Records are appended as the Group's events are delivered, so an
+// asynchronous Group appends them asynchronously as well;
+// It returns a function that stops appending records, and returns the
+// first error encountered while encoding or writing them, after which
+// no more records were appended.
+func (this *Group[T]) WAL(w io.Writer, codec Codec[T]) func() error {
+	mutex := sync.Mutex{}
+	encoder := json.NewEncoder(w)
+	sequence := uint64(0)
+	var failure error
+
+	unsubscribe := this.OnReadWrite(func(event ReadWriteEvent[T]) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if failure != nil {
+			return
+		}
+
+		var value []byte
+		if event.Current != nil {
+			encoded, err := codec.Encode(*event.Current)
+			if err != nil {
+				failure = err
+				return
+			}
+			value = encoded
+		}
+
+		sequence++
+		failure = encoder.Encode(walRecord{
+			Name:     event.SharefName,
+			Sequence: sequence,
+			Value:    value,
+		})
+	})
+
+	return func() error {
+		unsubscribe()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return failure
+	}
+}
+
+// ReplayWAL reconstructs the Group's state from a write-ahead log
+// produced by WAL(), applying its records in order; The Codec must
+// match the one the log was produced with;
+// Sharefs missing from the Group are created, and existing ones are
+// re-seeded with the logged values;
+// Like writes, re-seeded values must pass the Sharefs' validators;
+// It returns the first error encountered while decoding the log, or
+// the error of the first validator rejecting a logged value; The
+// records preceding it remain applied, and the rejected one leaves
+// its Sharef with its previous value.
+// *Note*: replaying is not a read-write operation, so no OnReadWrite
+// callbacks are invoked.
+func (this *Group[T]) ReplayWAL(r io.Reader, codec Codec[T]) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		var record walRecord
+		if err := decoder.Decode(&record); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var pointer *T
+		if record.Value != nil {
+			value, err := codec.Decode(record.Value)
+			if err != nil {
+				return err
+			}
+			pointer = &value
+		}
+
+		sharedref := this.adopt(record.Name, Dead[T](), false)
+		if err := sharedref.reseed(pointer); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package sharef
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Abandoned describes a Do() call whose Portal was not written to in
+// time, which stalls the call, and every other call waiting for the
+// Sharef's mutex, if any.
+type Abandoned struct {
+	// SharefName is the name of the Sharef within its Group, or empty
+	// if it doesn't belong to one.
+	SharefName string
+	// Timeout is the duration the write was awaited for.
+	Timeout time.Duration
+	// Stack is the stack of the goroutine which called Do(), as
+	// formatted by runtime.Stack().
+	Stack string
+}
+
+// watchdog holds the settings of a Sharef's watchdog, shared by all
+// of its copies; It is replaced as a whole, never modified.
+type watchdog struct {
+	timeout time.Duration
+	report  func(Abandoned)
+}
+
+// settings returns the watchdog's timeout and callback; The callback
+// is nil if the watchdog is disabled.
+func (this *watchdog) settings() (time.Duration, func(Abandoned)) {
+	if this == nil {
+		return 0, nil
+	}
+
+	return this.timeout, this.report
+}
+
+// WithWatchdog makes the Sharef, and all of its copies, report every
+// Do() call whose Portal was not written to within the given timeout; Each such call is reported once, to
+// the given callback, from a separate goroutine;
+// A nil callback, or a timeout lower than 1, disables the watchdog;
+// It returns the Sharef itself, so it can be chained to a constructor;
+// WithWatchdog *panics* if:
+// 1: the Sharef's value was never originally provided (zero value).
+// *Note*: the watchdog captures the stack of every goroutine when
+// reporting, it is meant for debugging.
+func (this Sharef[T]) WithWatchdog(timeout time.Duration, report func(Abandoned)) Sharef[T] {
+	if this.core == nil {
+		panic(ErrNilState)
+	}
+
+	if timeout < 1 || report == nil {
+		this.core.watchdog.Store(nil)
+		return this
+	}
+
+	this.core.watchdog.Store(&watchdog{
+		timeout: timeout,
+		report:  report,
+	})
+
+	return this
+}
+
+// watch starts the watchdog for a Do() call made from the goroutine
+// with the given identifier;
+// It returns a function to be called once the Portal is written to.
+func (this Sharef[T]) watch(timeout time.Duration, report func(Abandoned), goroutine string) func() {
+	name := ""
+	if this.core.member != nil {
+		name = this.core.member.name
+	}
+
+	timer := time.AfterFunc(timeout, func() {
+		report(Abandoned{
+			SharefName: name,
+			Timeout:    timeout,
+			Stack:      goroutineStack(goroutine),
+		})
+	})
+
+	return func() {
+		timer.Stop()
+	}
+}
+
+// goroutineID returns the identifier of the calling goroutine, as
+// formatted by runtime.Stack().
+func goroutineID() string {
+	buffer := make([]byte, 64)
+	buffer = buffer[:runtime.Stack(buffer, false)]
+
+	fields := bytes.Fields(buffer)
+	if len(fields) < 2 {
+		return ""
+	}
+
+	return string(fields[1])
+}
+
+// goroutineStack returns the stack of the goroutine with the given
+// identifier, or an empty string if it is not running anymore.
+func goroutineStack(id string) string {
+	buffer := make([]byte, 64*1024)
+	for {
+		length := runtime.Stack(buffer, true)
+		if length < len(buffer) {
+			buffer = buffer[:length]
+			break
+		}
+		buffer = make([]byte, 2*len(buffer))
+	}
+
+	prefix := "goroutine " + id + " "
+	for _, stack := range strings.Split(string(buffer), "\n\n") {
+		if strings.HasPrefix(stack, prefix) {
+			return stack
+		}
+	}
+
+	return ""
+}